@@ -2,10 +2,19 @@ package main
 
 import (
 	"context"
+	absencerepo "github.com/ilam072/avito-backend-internship/internal/absence/repo"
+	absencerest "github.com/ilam072/avito-backend-internship/internal/absence/rest"
+	absenceservice "github.com/ilam072/avito-backend-internship/internal/absence/service"
+	absenceworker "github.com/ilam072/avito-backend-internship/internal/absence/worker"
+	commentrepo "github.com/ilam072/avito-backend-internship/internal/comment/repo"
+	commentrest "github.com/ilam072/avito-backend-internship/internal/comment/rest"
+	commentservice "github.com/ilam072/avito-backend-internship/internal/comment/service"
 	"github.com/ilam072/avito-backend-internship/internal/config"
+	"github.com/ilam072/avito-backend-internship/internal/notification"
 	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
 	pullrequestrest "github.com/ilam072/avito-backend-internship/internal/pullrequest/rest"
 	pullrequestservice "github.com/ilam072/avito-backend-internship/internal/pullrequest/service"
+	pullrequestworker "github.com/ilam072/avito-backend-internship/internal/pullrequest/worker"
 	"github.com/ilam072/avito-backend-internship/internal/router"
 	teamrepo "github.com/ilam072/avito-backend-internship/internal/team/repo"
 	teamrest "github.com/ilam072/avito-backend-internship/internal/team/rest"
@@ -18,6 +27,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"net/http"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -39,23 +49,60 @@ func main() {
 	// Initialize validator
 	v := validator.New()
 
-	// Initialize user, team and pull request repositories
+	// Initialize user, team, pull request, absence and comment repositories
 	userRepo := userrepo.New(DB)
 	teamRepo := teamrepo.New(DB)
 	prRepo := prrepo.New(DB)
+	absenceRepo := absencerepo.New(DB)
+	commentRepo := commentrepo.New(DB)
 
-	// Initialize user, team and pull request services
-	user := userservice.NewUser(userRepo, teamRepo)
+	// Initialize transactor shared by services that span several repositories
+	transactor := db.NewTransactor(DB)
+
+	// Initialize reviewer selection strategy
+	selector, err := pullrequestservice.NewReviewerSelector(cfg.Reviewer.Strategy)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Str("strategy", cfg.Reviewer.Strategy).Msg("failed to initialize reviewer selector")
+	}
+
+	// Initialize user, team, pull request, absence and comment services
+	pullRequest := pullrequestservice.NewPullRequest(userRepo, prRepo, teamRepo, selector, transactor)
+	user := userservice.NewUser(userRepo, teamRepo, pullRequest, transactor)
 	item := teamservice.NewTeam(teamRepo)
-	pullRequest := pullrequestservice.NewPullRequest(userRepo, prRepo)
+	absence := absenceservice.NewAbsence(absenceRepo, userRepo, teamRepo)
+	comment := commentservice.NewComment(commentRepo, userRepo, prRepo)
+
+	// Initialize stale review escalation
+	escalator, err := pullrequestservice.NewEscalator(pullRequest, notification.NewLogNotifier(), pullrequestservice.EscalationPolicy{
+		RemindAfter:   cfg.Reminder.RemindAfter,
+		EscalateAfter: cfg.Reminder.EscalateAfter,
+		Mode:          cfg.Reminder.EscalationMode,
+	})
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("failed to initialize review escalator")
+	}
 
-	// Initialize user, team and pull request handlers
+	// Initialize user, team, pull request, absence and comment handlers
 	userHandler := userrest.NewUserHandler(user, v)
 	teamHandler := teamrest.NewTeamHandler(item, v)
 	prHandler := pullrequestrest.NewPullRequestHandler(pullRequest, v)
+	absenceHandler := absencerest.NewAbsenceHandler(absence, v)
+	commentHandler := commentrest.NewCommentHandler(comment, v)
 
 	// Initialize Gin engine and set routes
-	engine := router.New(userHandler, teamHandler, prHandler)
+	engine := router.New(userHandler, teamHandler, prHandler, absenceHandler, commentHandler)
+
+	// Start background workers
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		absenceworker.New(absence, cfg.Absence.SchedulerInterval).Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		pullrequestworker.New(escalator, cfg.Reminder.SchedulerInterval).Run(ctx)
+	}()
 
 	// Initialize and start http server
 	server := &http.Server{
@@ -79,5 +126,7 @@ func main() {
 		log.Logger.Error().Err(err).Msg("server shutdown failed")
 	}
 
+	workers.Wait()
+
 	DB.Close()
 }
@@ -0,0 +1,238 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/db"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type AbsenceRepo struct {
+	db *pgxpool.Pool
+}
+
+func New(db *pgxpool.Pool) *AbsenceRepo {
+	return &AbsenceRepo{db: db}
+}
+
+func (r *AbsenceRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+var (
+	ErrAbsenceNotFound = errors.New("absence not found")
+	ErrAbsenceOverlap  = errors.New("absence overlaps another one")
+)
+
+func (r *AbsenceRepo) CreateAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error) {
+	query := `
+		INSERT INTO absences (user_id, created_by, starts_at, ends_at, reason)
+		SELECT $1, $2, $3, $4, $5
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM absences
+			WHERE user_id = $1
+			  AND status IN ('SCHEDULED', 'ACTIVE')
+			  AND starts_at < $4
+			  AND ends_at > $3
+		)
+		RETURNING id, status, created_at
+	`
+
+	if err := r.conn(ctx).QueryRow(
+		ctx,
+		query,
+		absence.UserID,
+		absence.CreatedBy,
+		absence.StartsAt,
+		absence.EndsAt,
+		absence.Reason,
+	).Scan(&absence.ID, &absence.Status, &absence.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, ErrAbsenceOverlap
+		}
+		return domain.Absence{}, errutils.Wrap("failed to insert absence", err)
+	}
+
+	return absence, nil
+}
+
+func (r *AbsenceRepo) GetAbsenceByID(ctx context.Context, ID int64) (domain.Absence, error) {
+	query := `
+		SELECT id, user_id, created_by, starts_at, ends_at, reason, status, created_at
+		FROM absences
+		WHERE id = $1;
+	`
+
+	var a domain.Absence
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(
+		&a.ID,
+		&a.UserID,
+		&a.CreatedBy,
+		&a.StartsAt,
+		&a.EndsAt,
+		&a.Reason,
+		&a.Status,
+		&a.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, ErrAbsenceNotFound
+		}
+		return domain.Absence{}, errutils.Wrap("failed to get absence", err)
+	}
+
+	return a, nil
+}
+
+func (r *AbsenceRepo) GetAbsencesByUser(ctx context.Context, userID string) ([]domain.Absence, error) {
+	query := `
+		SELECT id, user_id, created_by, starts_at, ends_at, reason, status, created_at
+		FROM absences
+		WHERE user_id = $1
+		ORDER BY starts_at DESC
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query absences", err)
+	}
+	defer rows.Close()
+
+	var absences []domain.Absence
+	for rows.Next() {
+		var a domain.Absence
+		if err := rows.Scan(
+			&a.ID,
+			&a.UserID,
+			&a.CreatedBy,
+			&a.StartsAt,
+			&a.EndsAt,
+			&a.Reason,
+			&a.Status,
+			&a.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan absence", err)
+		}
+		absences = append(absences, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return absences, nil
+}
+
+// CancelAbsence drops a scheduled absence. An active one is cut short instead,
+// so that the scheduler restores availability on its next run.
+func (r *AbsenceRepo) CancelAbsence(ctx context.Context, ID int64) (domain.Absence, error) {
+	query := `
+		UPDATE absences
+		SET status = CASE WHEN status = 'SCHEDULED' THEN 'CANCELLED' ELSE status END,
+		    ends_at = CASE WHEN status = 'ACTIVE' THEN NOW() ELSE ends_at END
+		WHERE id = $1
+		  AND status IN ('SCHEDULED', 'ACTIVE')
+		RETURNING id, user_id, created_by, starts_at, ends_at, reason, status, created_at
+	`
+
+	var a domain.Absence
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(
+		&a.ID,
+		&a.UserID,
+		&a.CreatedBy,
+		&a.StartsAt,
+		&a.EndsAt,
+		&a.Reason,
+		&a.Status,
+		&a.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, ErrAbsenceNotFound
+		}
+		return domain.Absence{}, errutils.Wrap("failed to cancel absence", err)
+	}
+
+	return a, nil
+}
+
+// StartAbsences deactivates users whose absence has begun and remembers
+// whether they were active, so FinishAbsences can restore it. Absences that
+// were missed entirely are closed without touching the user.
+func (r *AbsenceRepo) StartAbsences(ctx context.Context, now time.Time) (int64, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return 0, errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE absences
+		SET status = 'FINISHED'
+		WHERE status = 'SCHEDULED'
+		  AND ends_at <= $1
+	`
+	if _, err := tx.Exec(ctx, query, now); err != nil {
+		return 0, errutils.Wrap("failed to close missed absences", err)
+	}
+
+	query = `
+		WITH started AS (
+			UPDATE absences a
+			SET status = 'ACTIVE',
+			    restore_active = u.is_active
+			FROM users u
+			WHERE u.id = a.user_id
+			  AND a.status = 'SCHEDULED'
+			  AND a.starts_at <= $1
+			RETURNING a.user_id
+		)
+		UPDATE users
+		SET is_active = FALSE,
+		    updated_at = NOW()
+		WHERE id IN (SELECT user_id FROM started)
+	`
+	res, err := tx.Exec(ctx, query, now)
+	if err != nil {
+		return 0, errutils.Wrap("failed to start absences", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// FinishAbsences closes absences that have ended and reactivates users that
+// were active when the absence started, unless their state was set explicitly
+// during the absence.
+func (r *AbsenceRepo) FinishAbsences(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		WITH finished AS (
+			UPDATE absences
+			SET status = 'FINISHED'
+			WHERE status = 'ACTIVE'
+			  AND ends_at <= $1
+			RETURNING user_id, restore_active
+		)
+		UPDATE users u
+		SET is_active = TRUE,
+		    updated_at = NOW()
+		FROM finished f
+		WHERE u.id = f.user_id
+		  AND f.restore_active
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, now)
+	if err != nil {
+		return 0, errutils.Wrap("failed to finish absences", err)
+	}
+
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/avito-backend-internship/internal/response"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type Absence interface {
+	CreateAbsence(ctx context.Context, req dto.CreateAbsenceRequest) (dto.Absence, error)
+	GetAbsences(ctx context.Context, userID string) (dto.AbsencesResponse, error)
+	CancelAbsence(ctx context.Context, req dto.CancelAbsenceRequest) (dto.Absence, error)
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type AbsenceHandler struct {
+	absence   Absence
+	validator Validator
+}
+
+func NewAbsenceHandler(absence Absence, validator Validator) *AbsenceHandler {
+	return &AbsenceHandler{absence: absence, validator: validator}
+}
+
+func (h *AbsenceHandler) CreateAbsence(c *gin.Context) {
+	var req dto.CreateAbsenceRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to create absence req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	absence, err := h.absence.CreateAbsence(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "only the user or their team lead can manage absences")
+			return
+		}
+		if errors.Is(err, domain.ErrAbsenceEnded) {
+			response.BadRequest(c, "absence must end in the future")
+			return
+		}
+		if errors.Is(err, domain.ErrAbsenceOverlap) {
+			response.Conflict(c, "ABSENCE_OVERLAP", "absence overlaps an existing one")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to create absence")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"absence": absence})
+}
+
+func (h *AbsenceHandler) GetAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		log.Logger.Warn().Msg("empty user id")
+		response.BadRequest(c, "invalid 'user_id' query parameter")
+		return
+	}
+
+	absences, err := h.absence.GetAbsences(c.Request.Context(), userID)
+	if err != nil {
+		log.Logger.Error().Err(err).Any("user_id", userID).Msg("failed to get absences")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, absences)
+}
+
+func (h *AbsenceHandler) CancelAbsence(c *gin.Context) {
+	var req dto.CancelAbsenceRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to cancel absence req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	absence, err := h.absence.CancelAbsence(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrAbsenceNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "only the user or their team lead can manage absences")
+			return
+		}
+		if errors.Is(err, domain.ErrAbsenceEnded) {
+			response.Conflict(c, "ABSENCE_ENDED", "absence is already finished or cancelled")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to cancel absence")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"absence": absence})
+}
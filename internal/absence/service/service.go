@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/absence/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	userrepo "github.com/ilam072/avito-backend-internship/internal/user/repo"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"time"
+)
+
+type AbsenceRepo interface {
+	CreateAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error)
+	GetAbsenceByID(ctx context.Context, ID int64) (domain.Absence, error)
+	GetAbsencesByUser(ctx context.Context, userID string) ([]domain.Absence, error)
+	CancelAbsence(ctx context.Context, ID int64) (domain.Absence, error)
+	StartAbsences(ctx context.Context, now time.Time) (int64, error)
+	FinishAbsences(ctx context.Context, now time.Time) (int64, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, ID string) (domain.User, error)
+}
+
+type TeamRepo interface {
+	GetTeamSettings(ctx context.Context, teamID int) (domain.TeamSettings, error)
+}
+
+type Absence struct {
+	absenceRepo AbsenceRepo
+	userRepo    UserRepo
+	teamRepo    TeamRepo
+}
+
+func NewAbsence(absenceRepo AbsenceRepo, userRepo UserRepo, teamRepo TeamRepo) *Absence {
+	return &Absence{absenceRepo: absenceRepo, userRepo: userRepo, teamRepo: teamRepo}
+}
+
+func (a *Absence) CreateAbsence(ctx context.Context, req dto.CreateAbsenceRequest) (dto.Absence, error) {
+	const op = "service.absence.Create"
+
+	if !req.EndsAt.After(time.Now()) {
+		return dto.Absence{}, errutils.Wrap(op, domain.ErrAbsenceEnded)
+	}
+
+	if err := a.checkActor(ctx, req.UserID, req.CreatedBy); err != nil {
+		return dto.Absence{}, errutils.Wrap(op, err)
+	}
+
+	absence, err := a.absenceRepo.CreateAbsence(ctx, domain.Absence{
+		UserID:    req.UserID,
+		CreatedBy: req.CreatedBy,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrAbsenceOverlap) {
+			return dto.Absence{}, errutils.Wrap(op, domain.ErrAbsenceOverlap)
+		}
+		return dto.Absence{}, errutils.Wrap(op, err)
+	}
+
+	return toAbsenceDTO(absence), nil
+}
+
+func (a *Absence) GetAbsences(ctx context.Context, userID string) (dto.AbsencesResponse, error) {
+	const op = "service.absence.GetAbsences"
+
+	absences, err := a.absenceRepo.GetAbsencesByUser(ctx, userID)
+	if err != nil {
+		return dto.AbsencesResponse{}, errutils.Wrap(op, err)
+	}
+
+	absencesResp := make([]dto.Absence, len(absences))
+	for i, absence := range absences {
+		absencesResp[i] = toAbsenceDTO(absence)
+	}
+
+	return dto.AbsencesResponse{
+		UserID:   userID,
+		Absences: absencesResp,
+	}, nil
+}
+
+func (a *Absence) CancelAbsence(ctx context.Context, req dto.CancelAbsenceRequest) (dto.Absence, error) {
+	const op = "service.absence.Cancel"
+
+	absence, err := a.absenceRepo.GetAbsenceByID(ctx, req.AbsenceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrAbsenceNotFound) {
+			return dto.Absence{}, errutils.Wrap(op, domain.ErrAbsenceNotFound)
+		}
+		return dto.Absence{}, errutils.Wrap(op, err)
+	}
+
+	if err := a.checkActor(ctx, absence.UserID, req.ActorID); err != nil {
+		return dto.Absence{}, errutils.Wrap(op, err)
+	}
+
+	absence, err = a.absenceRepo.CancelAbsence(ctx, req.AbsenceID)
+	if err != nil {
+		if errors.Is(err, repo.ErrAbsenceNotFound) {
+			return dto.Absence{}, errutils.Wrap(op, domain.ErrAbsenceEnded)
+		}
+		return dto.Absence{}, errutils.Wrap(op, err)
+	}
+
+	return toAbsenceDTO(absence), nil
+}
+
+// ApplySchedule flips availability of users whose absences started or ended by now.
+func (a *Absence) ApplySchedule(ctx context.Context) error {
+	const op = "service.absence.ApplySchedule"
+
+	now := time.Now()
+
+	if _, err := a.absenceRepo.FinishAbsences(ctx, now); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if _, err := a.absenceRepo.StartAbsences(ctx, now); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// checkActor allows managing an absence to the user themselves and to the lead of their team.
+func (a *Absence) checkActor(ctx context.Context, userID, actorID string) error {
+	user, err := a.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	if actorID == user.ID {
+		return nil
+	}
+
+	settings, err := a.teamRepo.GetTeamSettings(ctx, user.TeamID)
+	if err != nil {
+		return err
+	}
+	if settings.LeadID == nil || *settings.LeadID != actorID {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+func toAbsenceDTO(absence domain.Absence) dto.Absence {
+	return dto.Absence{
+		ID:        absence.ID,
+		UserID:    absence.UserID,
+		CreatedBy: absence.CreatedBy,
+		StartsAt:  absence.StartsAt,
+		EndsAt:    absence.EndsAt,
+		Reason:    absence.Reason,
+		Status:    absence.Status,
+	}
+}
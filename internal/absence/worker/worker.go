@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type Scheduler interface {
+	ApplySchedule(ctx context.Context) error
+}
+
+// Worker periodically applies absence boundaries until ctx is cancelled.
+// Transitions are conditional updates, so several instances can run at once.
+type Worker struct {
+	scheduler Scheduler
+	interval  time.Duration
+}
+
+func New(scheduler Scheduler, interval time.Duration) *Worker {
+	return &Worker{scheduler: scheduler, interval: interval}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.scheduler.ApplySchedule(ctx); err != nil && ctx.Err() == nil {
+			log.Logger.Error().Err(err).Msg("failed to apply absence schedule")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/db"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type CommentRepo struct {
+	db *pgxpool.Pool
+}
+
+func New(db *pgxpool.Pool) *CommentRepo {
+	return &CommentRepo{db: db}
+}
+
+func (r *CommentRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
+var (
+	ErrThreadNotFound = errors.New("comment thread not found")
+	ErrThreadResolved = errors.New("comment thread is resolved")
+)
+
+// CreateThread opens a thread on the PR with the given comment and marks the
+// PR as having unresolved threads.
+func (r *CommentRepo) CreateThread(ctx context.Context, prID string, comment domain.Comment) (domain.Thread, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return domain.Thread{}, errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	thread := domain.Thread{PullRequestID: prID, CreatedBy: comment.AuthorID}
+
+	query := `
+		INSERT INTO pr_comment_threads (pr_id, created_by)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRow(ctx, query, prID, comment.AuthorID).Scan(&thread.ID, &thread.CreatedAt); err != nil {
+		return domain.Thread{}, errutils.Wrap("failed to insert comment thread", err)
+	}
+
+	comment.ThreadID = thread.ID
+	if comment, err = insertComment(ctx, tx, comment); err != nil {
+		return domain.Thread{}, err
+	}
+	thread.Comments = []domain.Comment{comment}
+
+	query = `
+		UPDATE pull_requests
+		SET has_unresolved_threads = TRUE
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, query, prID); err != nil {
+		return domain.Thread{}, errutils.Wrap("failed to mark unresolved threads", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Thread{}, errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return thread, nil
+}
+
+// AddComment replies in an unresolved thread. The thread row is locked, so a
+// concurrent resolve either waits for the reply or rejects it.
+func (r *CommentRepo) AddComment(ctx context.Context, comment domain.Comment) (domain.Comment, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return domain.Comment{}, errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		SELECT resolved_at
+		FROM pr_comment_threads
+		WHERE id = $1
+		FOR SHARE
+	`
+	var resolvedAt *time.Time
+	if err := tx.QueryRow(ctx, query, comment.ThreadID).Scan(&resolvedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Comment{}, ErrThreadNotFound
+		}
+		return domain.Comment{}, errutils.Wrap("failed to lock comment thread", err)
+	}
+	if resolvedAt != nil {
+		return domain.Comment{}, ErrThreadResolved
+	}
+
+	if comment, err = insertComment(ctx, tx, comment); err != nil {
+		return domain.Comment{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Comment{}, errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return comment, nil
+}
+
+func insertComment(ctx context.Context, tx pgx.Tx, comment domain.Comment) (domain.Comment, error) {
+	query := `
+		INSERT INTO pr_comments (thread_id, author_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRow(ctx, query, comment.ThreadID, comment.AuthorID, comment.Body).Scan(&comment.ID, &comment.CreatedAt); err != nil {
+		return domain.Comment{}, errutils.Wrap("failed to insert comment", err)
+	}
+
+	return comment, nil
+}
+
+// ResolveThread closes an unresolved thread and refreshes whether its PR still
+// has unresolved ones.
+func (r *CommentRepo) ResolveThread(ctx context.Context, ID int64, actorID string) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE pr_comment_threads
+		SET resolved_by = $2,
+		    resolved_at = NOW()
+		WHERE id = $1
+		  AND resolved_at IS NULL
+		RETURNING pr_id
+	`
+	var prID string
+	if err := tx.QueryRow(ctx, query, ID, actorID).Scan(&prID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrThreadResolved
+		}
+		return errutils.Wrap("failed to resolve comment thread", err)
+	}
+
+	query = `
+		UPDATE pull_requests
+		SET has_unresolved_threads = EXISTS(
+			SELECT 1
+			FROM pr_comment_threads
+			WHERE pr_id = $1
+			  AND resolved_at IS NULL
+		)
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, query, prID); err != nil {
+		return errutils.Wrap("failed to refresh unresolved threads", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) GetThread(ctx context.Context, ID int64) (domain.Thread, error) {
+	query := `
+		SELECT t.id, t.pr_id, t.created_by, t.resolved_by, t.resolved_at, t.created_at,
+		       c.id, c.author_id, c.body, c.created_at
+		FROM pr_comment_threads t
+		JOIN pr_comments c ON c.thread_id = t.id
+		WHERE t.id = $1
+		ORDER BY c.created_at, c.id
+	`
+
+	threads, err := r.queryThreads(ctx, query, ID)
+	if err != nil {
+		return domain.Thread{}, err
+	}
+	if len(threads) == 0 {
+		return domain.Thread{}, ErrThreadNotFound
+	}
+
+	return threads[0], nil
+}
+
+// GetThreads returns the threads of the PR with their comments, oldest first.
+func (r *CommentRepo) GetThreads(ctx context.Context, prID string) ([]domain.Thread, error) {
+	query := `
+		SELECT t.id, t.pr_id, t.created_by, t.resolved_by, t.resolved_at, t.created_at,
+		       c.id, c.author_id, c.body, c.created_at
+		FROM pr_comment_threads t
+		JOIN pr_comments c ON c.thread_id = t.id
+		WHERE t.pr_id = $1
+		ORDER BY t.created_at, t.id, c.created_at, c.id
+	`
+
+	return r.queryThreads(ctx, query, prID)
+}
+
+// queryThreads scans rows of threads joined with their comments, grouped by
+// thread.
+func (r *CommentRepo) queryThreads(ctx context.Context, query string, args ...any) ([]domain.Thread, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query comment threads", err)
+	}
+	defer rows.Close()
+
+	var threads []domain.Thread
+	for rows.Next() {
+		var (
+			t domain.Thread
+			c domain.Comment
+		)
+		if err := rows.Scan(
+			&t.ID,
+			&t.PullRequestID,
+			&t.CreatedBy,
+			&t.ResolvedBy,
+			&t.ResolvedAt,
+			&t.CreatedAt,
+			&c.ID,
+			&c.AuthorID,
+			&c.Body,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan comment", err)
+		}
+		c.ThreadID = t.ID
+
+		if n := len(threads); n > 0 && threads[n-1].ID == t.ID {
+			threads[n-1].Comments = append(threads[n-1].Comments, c)
+			continue
+		}
+		t.Comments = []domain.Comment{c}
+		threads = append(threads, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return threads, nil
+}
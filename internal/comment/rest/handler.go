@@ -0,0 +1,130 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ilam072/avito-backend-internship/internal/response"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type Comment interface {
+	CreateComment(ctx context.Context, req dto.CreateCommentRequest) (dto.Comment, error)
+	GetComments(ctx context.Context, prID string) (dto.CommentsResponse, error)
+	ResolveThread(ctx context.Context, req dto.ResolveThreadRequest) (dto.Thread, error)
+}
+
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+type CommentHandler struct {
+	comment   Comment
+	validator Validator
+}
+
+func NewCommentHandler(comment Comment, validator Validator) *CommentHandler {
+	return &CommentHandler{comment: comment, validator: validator}
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req dto.CreateCommentRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to create comment req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	comment, err := h.comment.CreateComment(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) ||
+			errors.Is(err, domain.ErrUserNotFound) ||
+			errors.Is(err, domain.ErrThreadNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "only team members and assigned reviewers can comment on the PR")
+			return
+		}
+		if errors.Is(err, domain.ErrThreadResolved) {
+			response.Conflict(c, "THREAD_RESOLVED", "thread is resolved")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to create comment")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"comment": comment})
+}
+
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		log.Logger.Warn().Msg("empty pull request id")
+		response.BadRequest(c, "invalid 'pull_request_id' query parameter")
+		return
+	}
+
+	comments, err := h.comment.GetComments(c.Request.Context(), prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("pull_request_id", prID).Msg("failed to get comments")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) ResolveThread(c *gin.Context) {
+	var req dto.ResolveThreadRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to resolve thread req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	thread, err := h.comment.ResolveThread(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) ||
+			errors.Is(err, domain.ErrUserNotFound) ||
+			errors.Is(err, domain.ErrThreadNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			response.Forbidden(c, "only team members and assigned reviewers can resolve threads of the PR")
+			return
+		}
+		if errors.Is(err, domain.ErrThreadResolved) {
+			response.Conflict(c, "THREAD_RESOLVED", "thread is already resolved")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to resolve thread")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"thread": thread})
+}
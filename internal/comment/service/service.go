@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/comment/repo"
+	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	userrepo "github.com/ilam072/avito-backend-internship/internal/user/repo"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+)
+
+type CommentRepo interface {
+	CreateThread(ctx context.Context, prID string, comment domain.Comment) (domain.Thread, error)
+	AddComment(ctx context.Context, comment domain.Comment) (domain.Comment, error)
+	ResolveThread(ctx context.Context, ID int64, actorID string) error
+	GetThread(ctx context.Context, ID int64) (domain.Thread, error)
+	GetThreads(ctx context.Context, prID string) ([]domain.Thread, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, ID string) (domain.User, error)
+}
+
+type PullRequestRepo interface {
+	GetPullRequestByID(ctx context.Context, ID string) (domain.PullRequest, error)
+	IsUserAssignedForPR(ctx context.Context, prID, userID string) (bool, error)
+}
+
+type Comment struct {
+	commentRepo CommentRepo
+	userRepo    UserRepo
+	prRepo      PullRequestRepo
+}
+
+func NewComment(commentRepo CommentRepo, userRepo UserRepo, prRepo PullRequestRepo) *Comment {
+	return &Comment{commentRepo: commentRepo, userRepo: userRepo, prRepo: prRepo}
+}
+
+// CreateComment opens a thread on the PR, or replies in the given one.
+func (s *Comment) CreateComment(ctx context.Context, req dto.CreateCommentRequest) (dto.Comment, error) {
+	const op = "service.comment.Create"
+
+	pr, err := s.getPullRequest(ctx, req.PullRequestID)
+	if err != nil {
+		return dto.Comment{}, errutils.Wrap(op, err)
+	}
+
+	if err := s.checkParticipant(ctx, pr, req.AuthorID); err != nil {
+		return dto.Comment{}, errutils.Wrap(op, err)
+	}
+
+	comment := domain.Comment{
+		ThreadID: req.ThreadID,
+		AuthorID: req.AuthorID,
+		Body:     req.Body,
+	}
+
+	if req.ThreadID == 0 {
+		thread, err := s.commentRepo.CreateThread(ctx, pr.ID, comment)
+		if err != nil {
+			return dto.Comment{}, errutils.Wrap(op, err)
+		}
+		return toCommentDTO(thread.Comments[0]), nil
+	}
+
+	if _, err := s.getThread(ctx, pr.ID, req.ThreadID); err != nil {
+		return dto.Comment{}, errutils.Wrap(op, err)
+	}
+
+	comment, err = s.commentRepo.AddComment(ctx, comment)
+	if err != nil {
+		if errors.Is(err, repo.ErrThreadNotFound) {
+			return dto.Comment{}, errutils.Wrap(op, domain.ErrThreadNotFound)
+		}
+		if errors.Is(err, repo.ErrThreadResolved) {
+			return dto.Comment{}, errutils.Wrap(op, domain.ErrThreadResolved)
+		}
+		return dto.Comment{}, errutils.Wrap(op, err)
+	}
+
+	return toCommentDTO(comment), nil
+}
+
+func (s *Comment) GetComments(ctx context.Context, prID string) (dto.CommentsResponse, error) {
+	const op = "service.comment.GetComments"
+
+	pr, err := s.getPullRequest(ctx, prID)
+	if err != nil {
+		return dto.CommentsResponse{}, errutils.Wrap(op, err)
+	}
+
+	threads, err := s.commentRepo.GetThreads(ctx, prID)
+	if err != nil {
+		return dto.CommentsResponse{}, errutils.Wrap(op, err)
+	}
+
+	threadsResp := make([]dto.Thread, len(threads))
+	for i, thread := range threads {
+		threadsResp[i] = toThreadDTO(thread)
+	}
+
+	return dto.CommentsResponse{
+		PullRequestID: prID,
+		OpenThreads:   pr.OpenThreads,
+		Threads:       threadsResp,
+	}, nil
+}
+
+// ResolveThread closes a thread. Anyone who may comment on the PR may resolve
+// its threads.
+func (s *Comment) ResolveThread(ctx context.Context, req dto.ResolveThreadRequest) (dto.Thread, error) {
+	const op = "service.comment.ResolveThread"
+
+	thread, err := s.commentRepo.GetThread(ctx, req.ThreadID)
+	if err != nil {
+		if errors.Is(err, repo.ErrThreadNotFound) {
+			return dto.Thread{}, errutils.Wrap(op, domain.ErrThreadNotFound)
+		}
+		return dto.Thread{}, errutils.Wrap(op, err)
+	}
+
+	pr, err := s.getPullRequest(ctx, thread.PullRequestID)
+	if err != nil {
+		return dto.Thread{}, errutils.Wrap(op, err)
+	}
+
+	if err := s.checkParticipant(ctx, pr, req.ActorID); err != nil {
+		return dto.Thread{}, errutils.Wrap(op, err)
+	}
+
+	if err := s.commentRepo.ResolveThread(ctx, thread.ID, req.ActorID); err != nil {
+		if errors.Is(err, repo.ErrThreadResolved) {
+			return dto.Thread{}, errutils.Wrap(op, domain.ErrThreadResolved)
+		}
+		return dto.Thread{}, errutils.Wrap(op, err)
+	}
+
+	thread, err = s.commentRepo.GetThread(ctx, thread.ID)
+	if err != nil {
+		return dto.Thread{}, errutils.Wrap(op, err)
+	}
+
+	return toThreadDTO(thread), nil
+}
+
+// checkParticipant allows commenting to the members of the author's team and
+// to the reviewers assigned to the PR.
+func (s *Comment) checkParticipant(ctx context.Context, pr domain.PullRequest, userID string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+	if user.TeamID == author.TeamID {
+		return nil
+	}
+
+	assigned, err := s.prRepo.IsUserAssignedForPR(ctx, pr.ID, user.ID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+func (s *Comment) getPullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := s.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return domain.PullRequest{}, domain.ErrPullRequestNotFound
+		}
+		return domain.PullRequest{}, err
+	}
+	return pr, nil
+}
+
+// getThread returns a thread of the PR. A thread of another PR is reported as
+// not found.
+func (s *Comment) getThread(ctx context.Context, prID string, threadID int64) (domain.Thread, error) {
+	thread, err := s.commentRepo.GetThread(ctx, threadID)
+	if err != nil {
+		if errors.Is(err, repo.ErrThreadNotFound) {
+			return domain.Thread{}, domain.ErrThreadNotFound
+		}
+		return domain.Thread{}, err
+	}
+	if thread.PullRequestID != prID {
+		return domain.Thread{}, domain.ErrThreadNotFound
+	}
+	return thread, nil
+}
+
+func toThreadDTO(thread domain.Thread) dto.Thread {
+	comments := make([]dto.Comment, len(thread.Comments))
+	for i, comment := range thread.Comments {
+		comments[i] = toCommentDTO(comment)
+	}
+
+	return dto.Thread{
+		ID:         thread.ID,
+		CreatedBy:  thread.CreatedBy,
+		Resolved:   thread.Resolved(),
+		ResolvedBy: thread.ResolvedBy,
+		ResolvedAt: thread.ResolvedAt,
+		Comments:   comments,
+		CreatedAt:  thread.CreatedAt,
+	}
+}
+
+func toCommentDTO(comment domain.Comment) dto.Comment {
+	return dto.Comment{
+		ID:        comment.ID,
+		ThreadID:  comment.ThreadID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+	}
+}
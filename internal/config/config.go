@@ -3,11 +3,15 @@ package config
 import (
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
+	"time"
 )
 
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
+	Server   ServerConfig
+	DB       DBConfig
+	Reviewer ReviewerConfig
+	Absence  AbsenceConfig
+	Reminder ReminderConfig
 }
 
 type DBConfig struct {
@@ -23,6 +27,21 @@ type ServerConfig struct {
 	HTTPPort string `env:"HTTP_PORT"`
 }
 
+type ReviewerConfig struct {
+	Strategy string `env:"REVIEWER_STRATEGY" envDefault:"least_loaded"`
+}
+
+type AbsenceConfig struct {
+	SchedulerInterval time.Duration `env:"ABSENCE_SCHEDULER_INTERVAL" envDefault:"1m"`
+}
+
+type ReminderConfig struct {
+	SchedulerInterval time.Duration `env:"REMINDER_SCHEDULER_INTERVAL" envDefault:"5m"`
+	RemindAfter       time.Duration `env:"REMINDER_REMIND_AFTER" envDefault:"24h"`
+	EscalateAfter     time.Duration `env:"REMINDER_ESCALATE_AFTER" envDefault:"48h"`
+	EscalationMode    string        `env:"REMINDER_ESCALATION_MODE" envDefault:"lead"`
+}
+
 func MustLoad() *Config {
 	cfg := &Config{}
 
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// LogNotifier delivers notifications to the service log.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification domain.Notification) error {
+	log.Logger.Info().
+		Str("kind", notification.Kind).
+		Str("recipient_id", notification.RecipientID).
+		Str("pr_id", notification.PullRequestID).
+		Msg(notification.Message)
+
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/db"
 	"github.com/ilam072/avito-backend-internship/pkg/errutils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
 type PullRequestsRepo struct {
@@ -17,12 +19,30 @@ func New(db *pgxpool.Pool) *PullRequestsRepo {
 	return &PullRequestsRepo{db: db}
 }
 
+func (r *PullRequestsRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
 var (
 	ErrPullRequestNotFound = errors.New("pull request not found")
+	ErrStatusChanged       = errors.New("pull request status changed")
+	ErrDependencyCycle     = errors.New("pull request dependency cycle")
 )
 
+// assignmentFactors is the JSONB representation of domain.AssignmentFactors.
+type assignmentFactors struct {
+	Source         string     `json:"source"`
+	Rule           string     `json:"rule,omitempty"`
+	FromFallback   bool       `json:"from_fallback"`
+	TeamID         int        `json:"team_id"`
+	Level          string     `json:"level,omitempty"`
+	OpenReviews    int        `json:"open_reviews"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+	ReplacedUserID string     `json:"replaced_user_id,omitempty"`
+}
+
 func (r *PullRequestsRepo) CreatePullRequest(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.conn(ctx).Begin(ctx)
 	if err != nil {
 		return domain.PullRequest{}, errutils.Wrap("failed to begin transaction", err)
 	}
@@ -31,82 +51,83 @@ func (r *PullRequestsRepo) CreatePullRequest(ctx context.Context, pr domain.Pull
 	}()
 
 	query := `
-		INSERT INTO pull_requests (id, author_id, name)
-		VALUES ($1, $2, $3)
+		INSERT INTO pull_requests (id, author_id, name, under_staffed, status, changed_files)
+		VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'OPEN'), $6)
 		RETURNING status, created_at
 	`
-	if err = tx.QueryRow(ctx, query, pr.ID, pr.AuthorID, pr.Name).Scan(&pr.Status, &pr.CreatedAt); err != nil {
+	if err = tx.QueryRow(
+		ctx,
+		query,
+		pr.ID,
+		pr.AuthorID,
+		pr.Name,
+		pr.UnderStaffed,
+		pr.Status,
+		filesOrEmpty(pr.Files),
+	).Scan(&pr.Status, &pr.CreatedAt); err != nil {
 		return domain.PullRequest{}, errutils.Wrap("failed to insert pull request", err)
 	}
 
 	query = `
-		SELECT u.id
-		FROM users u
-		JOIN users a ON a.id = $1
-		WHERE u.team_id = a.team_id
-		  AND u.is_active = TRUE
-		  AND u.id != $1
-		ORDER BY RANDOM()
-		LIMIT 2
+		INSERT INTO pr_events (pr_id, kind, actor_id, after)
+		VALUES ($1, 'CREATED', $2, jsonb_build_object(
+			'name', $3::TEXT,
+			'status', $4::TEXT,
+			'changed_files', $5::TEXT[],
+			'labels', COALESCE($6::TEXT[], '{}')
+		))
 	`
-
-	rows, err := tx.Query(ctx, query, pr.AuthorID)
-	if err != nil {
-		return domain.PullRequest{}, errutils.Wrap("failed to select reviewers", err)
+	if _, err := tx.Exec(ctx, query, pr.ID, pr.AuthorID, pr.Name, pr.Status, filesOrEmpty(pr.Files), pr.Labels); err != nil {
+		return domain.PullRequest{}, errutils.Wrap("failed to insert created event", err)
 	}
-	defer rows.Close()
 
-	var reviewers []string
-	for rows.Next() {
-		var rID string
-		if err := rows.Scan(&rID); err != nil {
-			return domain.PullRequest{}, errutils.Wrap("failed to scan reviewer", err)
+	// Reviewers picked on creation are chosen by the service, not the author.
+	for _, a := range pr.Assignments {
+		if _, err := tx.Exec(ctx, insertReviewerQuery, pr.ID, a.ReviewerID, a.Strategy, a.PoolSize, assignmentFactors(a.Factors), a.DueAt, nil); err != nil {
+			return domain.PullRequest{}, errutils.Wrap("failed to insert reviewer", err)
 		}
-		reviewers = append(reviewers, rID)
 	}
 
-	if err := rows.Err(); err != nil {
-		return domain.PullRequest{}, errutils.Wrap("error iterating reviewers", err)
-	}
-
-	query = `
-			INSERT INTO pr_reviewers (pr_id, reviewer_id)
-			VALUES ($1, $2)
-		`
-
-	for _, rID := range reviewers {
-		if _, err := tx.Exec(ctx, query, pr.ID, rID); err != nil {
-			return domain.PullRequest{}, errutils.Wrap("failed to insert reviewer", err)
-		}
+	if _, err := tx.Exec(ctx, insertLabelsQuery, pr.ID, pr.Labels); err != nil {
+		return domain.PullRequest{}, errutils.Wrap("failed to insert labels", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return domain.PullRequest{}, errutils.Wrap("failed to commit transaction", err)
 	}
 
-	pr.Reviewers = make([]string, len(reviewers))
-	for i, rID := range reviewers {
-		pr.Reviewers[i] = rID
-	}
-
 	return pr, nil
 }
 
 func (r *PullRequestsRepo) GetPullRequestByID(ctx context.Context, ID string) (domain.PullRequest, error) {
 	query := `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT id, name, author_id, status, changed_files,
+		       ARRAY(SELECT l.label FROM pr_labels l WHERE l.pr_id = pull_requests.id ORDER BY l.label),
+		       ARRAY(SELECT d.parent_id FROM pr_dependencies d WHERE d.pr_id = pull_requests.id ORDER BY d.parent_id),
+		       under_staffed, auto_merge, merge_override, merged_by, auto_merged, has_unresolved_threads,
+		       created_at, merged_at, closed_at
 		FROM pull_requests
 		WHERE id = $1;
 	`
 
 	var pr domain.PullRequest
-	if err := r.db.QueryRow(ctx, query, ID).Scan(
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(
 		&pr.ID,
 		&pr.Name,
 		&pr.AuthorID,
 		&pr.Status,
+		&pr.Files,
+		&pr.Labels,
+		&pr.ParentIDs,
+		&pr.UnderStaffed,
+		&pr.AutoMerge,
+		&pr.MergeOverride,
+		&pr.MergedBy,
+		&pr.AutoMerged,
+		&pr.OpenThreads,
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.ClosedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.PullRequest{}, ErrPullRequestNotFound
@@ -118,6 +139,22 @@ func (r *PullRequestsRepo) GetPullRequestByID(ctx context.Context, ID string) (d
 
 }
 
+// LockPullRequest takes the row lock of the PR until the transaction ends.
+// Writers that add verdicts, reviewers, parents or timeline events to the PR
+// wait for it, since their rows reference the locked one.
+func (r *PullRequestsRepo) LockPullRequest(ctx context.Context, ID string) error {
+	query := `SELECT id FROM pull_requests WHERE id = $1 FOR UPDATE`
+
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(&ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPullRequestNotFound
+		}
+		return errutils.Wrap("failed to lock pr", err)
+	}
+
+	return nil
+}
+
 func (r *PullRequestsRepo) GetPullRequestReviewers(ctx context.Context, ID string) ([]string, error) {
 	query := `
 		SELECT reviewer_id
@@ -125,7 +162,7 @@ func (r *PullRequestsRepo) GetPullRequestReviewers(ctx context.Context, ID strin
 		WHERE pr_id = $1
 	`
 
-	rows, err := r.db.Query(ctx, query, ID)
+	rows, err := r.conn(ctx).Query(ctx, query, ID)
 	if err != nil {
 		return nil, errutils.Wrap("failed to get reviewers", err)
 	}
@@ -147,17 +184,50 @@ func (r *PullRequestsRepo) GetPullRequestReviewers(ctx context.Context, ID strin
 	return reviewersIDs, nil
 }
 
-func (r *PullRequestsRepo) MergePullRequest(ctx context.Context, ID string) (domain.PullRequest, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+// UpdateStatus moves the PR from one status to another and records the change
+// made by actorID in the timeline. ErrStatusChanged means the PR is no longer
+// in the expected status.
+func (r *PullRequestsRepo) UpdateStatus(ctx context.Context, ID, from, to string, actorID *string) error {
+	query := `
+		WITH updated AS (
+			UPDATE pull_requests
+			SET status = $3::TEXT,
+			    closed_at = CASE WHEN $4 THEN NOW() END
+			WHERE id = $1 AND status = $2::TEXT
+			RETURNING id
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+		SELECT id, 'STATUS_CHANGED', $5, jsonb_build_object('status', $2::TEXT), jsonb_build_object('status', $3::TEXT)
+		FROM updated
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, ID, from, to, to == domain.StatusClosed, actorID)
+	if err != nil {
+		return errutils.Wrap("failed to update pr status", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrStatusChanged
+	}
+
+	return nil
+}
+
+// MergePullRequest marks the PR as merged and records whether the approval
+// check was overridden and by whom, both on the PR and in its timeline.
+// Merging an already merged PR keeps the original record. ErrStatusChanged
+// means the locked PR is neither open nor merged.
+func (r *PullRequestsRepo) MergePullRequest(ctx context.Context, ID string, opts domain.MergeOptions) (domain.PullRequest, error) {
+	tx, err := r.conn(ctx).Begin(ctx)
 	if err != nil {
 		return domain.PullRequest{}, errutils.Wrap("failed to begin transaction", err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	query := `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT id, name, author_id, status, merge_override, merged_by, auto_merged, created_at, merged_at
 		FROM pull_requests
 		WHERE id = $1
+		FOR UPDATE
 	`
 
 	var pr domain.PullRequest
@@ -166,6 +236,9 @@ func (r *PullRequestsRepo) MergePullRequest(ctx context.Context, ID string) (dom
 		&pr.Name,
 		&pr.AuthorID,
 		&pr.Status,
+		&pr.MergeOverride,
+		&pr.MergedBy,
+		&pr.AutoMerged,
 		&pr.CreatedAt,
 		&pr.MergedAt,
 	); err != nil {
@@ -175,17 +248,44 @@ func (r *PullRequestsRepo) MergePullRequest(ctx context.Context, ID string) (dom
 		return domain.PullRequest{}, errutils.Wrap("failed to get pull request", err)
 	}
 
+	if pr.Status != "MERGED" && pr.Status != "OPEN" {
+		return domain.PullRequest{}, ErrStatusChanged
+	}
+
 	if pr.Status != "MERGED" {
+		from := pr.Status
+
 		query = `
 			UPDATE pull_requests
 			SET status = 'MERGED',
-			    merged_at = NOW()
+			    merged_at = NOW(),
+			    merge_override = $2,
+			    merged_by = $3,
+			    auto_merged = $4
 			WHERE id = $1
-			RETURNING status, merged_at
+			RETURNING status, merge_override, merged_by, auto_merged, merged_at
 		`
-		if err = tx.QueryRow(ctx, query, ID).Scan(&pr.Status, &pr.MergedAt); err != nil {
+		if err = tx.QueryRow(ctx, query, ID, opts.Override, opts.MergedBy, opts.Automatic).Scan(
+			&pr.Status,
+			&pr.MergeOverride,
+			&pr.MergedBy,
+			&pr.AutoMerged,
+			&pr.MergedAt,
+		); err != nil {
 			return domain.PullRequest{}, errutils.Wrap("failed to merge pull request", err)
 		}
+
+		query = `
+			INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+			VALUES ($1, 'MERGED', $2, jsonb_build_object('status', $3::TEXT), jsonb_build_object(
+				'status', $4::TEXT,
+				'merge_override', $5::BOOLEAN,
+				'auto_merged', $6::BOOLEAN
+			))
+		`
+		if _, err := tx.Exec(ctx, query, ID, pr.MergedBy, from, pr.Status, pr.MergeOverride, pr.AutoMerged); err != nil {
+			return domain.PullRequest{}, errutils.Wrap("failed to insert merged event", err)
+		}
 	}
 
 	query = `SELECT reviewer_id FROM pr_reviewers WHERE pr_id = $1`
@@ -223,28 +323,302 @@ func (r *PullRequestsRepo) IsUserAssignedForPR(ctx context.Context, prID, userID
 	`
 
 	var exists bool
-	if err := r.db.QueryRow(ctx, query, prID, userID).Scan(&exists); err != nil {
+	if err := r.conn(ctx).QueryRow(ctx, query, prID, userID).Scan(&exists); err != nil {
 		return false, errutils.Wrap("failed to check if user exists", err)
 	}
 
 	return exists, nil
 }
 
-func (r *PullRequestsRepo) UpdateReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
+func (r *PullRequestsRepo) GetPullRequestAssignments(ctx context.Context, ID string) ([]domain.Assignment, error) {
 	query := `
-		UPDATE pr_reviewers 
-		SET reviewer_id = $1 
-		WHERE pr_id = $2 AND reviewer_id = $3
+		SELECT reviewer_id, assigned_at, due_at, strategy, pool_size, factors
+		FROM pr_reviewers
+		WHERE pr_id = $1
+		ORDER BY assigned_at, reviewer_id
 	`
 
-	if _, err := r.db.Exec(ctx, query, newUserID, prID, oldUserID); err != nil {
+	rows, err := r.conn(ctx).Query(ctx, query, ID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get assignments", err)
+	}
+	defer rows.Close()
+
+	var assignments []domain.Assignment
+	for rows.Next() {
+		var (
+			a       domain.Assignment
+			factors assignmentFactors
+		)
+		if err := rows.Scan(&a.ReviewerID, &a.AssignedAt, &a.DueAt, &a.Strategy, &a.PoolSize, &factors); err != nil {
+			return nil, errutils.Wrap("failed to scan assignment", err)
+		}
+		a.Factors = domain.AssignmentFactors(factors)
+		assignments = append(assignments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating assignments", err)
+	}
+
+	return assignments, nil
+}
+
+// UpdateReviewer hands the review over to a.ReviewerID on behalf of actorID.
+// The row is treated as a fresh assignment, so assigned_at and the explanation
+// are replaced. The replaced assignment is kept in the timeline and its period
+// is closed.
+func (r *PullRequestsRepo) UpdateReviewer(ctx context.Context, prID, oldUserID string, a domain.Assignment, actorID *string) error {
+	query := `
+		WITH replaced AS (
+			SELECT reviewer_id, assigned_at, due_at, strategy, pool_size, factors
+			FROM pr_reviewers
+			WHERE pr_id = $6 AND reviewer_id = $7
+			FOR UPDATE
+		), updated AS (
+			UPDATE pr_reviewers
+			SET reviewer_id = $1,
+			    assigned_at = NOW(),
+			    strategy = $2,
+			    pool_size = $3,
+			    factors = $4,
+			    due_at = $5,
+			    reminded_at = NULL,
+			    escalated_at = NULL
+			WHERE pr_id = $6 AND reviewer_id = $7
+			RETURNING reviewer_id, assigned_at, due_at, strategy, pool_size, factors
+		), closed AS (
+			UPDATE pr_assignment_history
+			SET valid_to = NOW()
+			WHERE pr_id = $6 AND reviewer_id = $7 AND valid_to IS NULL
+		), opened AS (
+			INSERT INTO pr_assignment_history (pr_id, reviewer_id, valid_from)
+			SELECT $6, reviewer_id, assigned_at
+			FROM updated
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+		SELECT $6, 'REASSIGNED', $8, to_jsonb(replaced), to_jsonb(updated)
+		FROM replaced, updated
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, a.ReviewerID, a.Strategy, a.PoolSize, assignmentFactors(a.Factors), a.DueAt, prID, oldUserID, actorID); err != nil {
 		return errutils.Wrap("failed to update reviewer", err)
 	}
 
 	return nil
 }
 
-func (r *PullRequestsRepo) GetPRsWhereUserIsReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+// insertReviewerQuery assigns a reviewer, opens their assignment period and
+// records the assignment made by the actor in $7 in the timeline.
+const insertReviewerQuery = `
+	WITH assigned AS (
+		INSERT INTO pr_reviewers (pr_id, reviewer_id, strategy, pool_size, factors, due_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING reviewer_id, assigned_at, due_at, strategy, pool_size, factors
+	), opened AS (
+		INSERT INTO pr_assignment_history (pr_id, reviewer_id, valid_from)
+		SELECT $1, reviewer_id, assigned_at
+		FROM assigned
+	)
+	INSERT INTO pr_events (pr_id, kind, actor_id, after)
+	SELECT $1, 'ASSIGNED', $7, to_jsonb(assigned)
+	FROM assigned
+`
+
+func (r *PullRequestsRepo) AddReviewer(ctx context.Context, prID string, a domain.Assignment, actorID *string) error {
+	if _, err := r.conn(ctx).Exec(ctx, insertReviewerQuery, prID, a.ReviewerID, a.Strategy, a.PoolSize, assignmentFactors(a.Factors), a.DueAt, actorID); err != nil {
+		return errutils.Wrap("failed to insert reviewer", err)
+	}
+
+	return nil
+}
+
+// RemoveReviewer unassigns the reviewer on behalf of actorID, closes their
+// assignment period and keeps the dropped assignment in the timeline.
+func (r *PullRequestsRepo) RemoveReviewer(ctx context.Context, prID, userID string, actorID *string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM pr_reviewers
+			WHERE pr_id = $1 AND reviewer_id = $2
+			RETURNING reviewer_id, assigned_at, due_at, strategy, pool_size, factors
+		), closed AS (
+			UPDATE pr_assignment_history
+			SET valid_to = NOW()
+			WHERE pr_id = $1 AND reviewer_id = $2 AND valid_to IS NULL
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before)
+		SELECT $1, 'UNASSIGNED', $3, to_jsonb(removed)
+		FROM removed
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, userID, actorID); err != nil {
+		return errutils.Wrap("failed to remove reviewer", err)
+	}
+
+	return nil
+}
+
+func (r *PullRequestsRepo) SetUnderStaffed(ctx context.Context, prID string, underStaffed bool) error {
+	query := `
+		UPDATE pull_requests
+		SET under_staffed = $1
+		WHERE id = $2
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, underStaffed, prID); err != nil {
+		return errutils.Wrap("failed to update under_staffed", err)
+	}
+
+	return nil
+}
+
+// DeclineReview records that the user refused to review the PR. A repeated
+// decline overwrites the reason, the timeline keeps every one.
+func (r *PullRequestsRepo) DeclineReview(ctx context.Context, prID, userID, reason string) error {
+	query := `
+		WITH declined AS (
+			INSERT INTO pr_declines (pr_id, user_id, reason)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (pr_id, user_id) DO UPDATE
+			SET reason = EXCLUDED.reason,
+			    declined_at = NOW()
+			RETURNING pr_id, user_id, reason
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, after)
+		SELECT pr_id, 'DECLINED', user_id, jsonb_build_object('reason', reason)
+		FROM declined
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, userID, reason); err != nil {
+		return errutils.Wrap("failed to insert decline", err)
+	}
+
+	return nil
+}
+
+func (r *PullRequestsRepo) GetDecliners(ctx context.Context, prID string) ([]string, error) {
+	query := `
+		SELECT user_id
+		FROM pr_declines
+		WHERE pr_id = $1
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get decliners", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, errutils.Wrap("failed to scan decliner", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating decliners", err)
+	}
+
+	return userIDs, nil
+}
+
+// SubmitReview stores the reviewer's verdict on the PR and records it in the
+// timeline next to the previous one. A COMMENTED verdict updates the comment
+// but keeps an earlier decisive verdict.
+func (r *PullRequestsRepo) SubmitReview(ctx context.Context, review domain.Review) (domain.Review, error) {
+	query := `
+		WITH previous AS (
+			SELECT verdict, comment, submitted_at
+			FROM pr_reviews
+			WHERE pr_id = $1 AND reviewer_id = $2
+			FOR UPDATE
+		), stored AS (
+			INSERT INTO pr_reviews (pr_id, reviewer_id, verdict, comment)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (pr_id, reviewer_id) DO UPDATE
+			SET verdict = CASE
+			        WHEN EXCLUDED.verdict = 'COMMENTED' THEN pr_reviews.verdict
+			        ELSE EXCLUDED.verdict
+			    END,
+			    comment = EXCLUDED.comment,
+			    submitted_at = NOW()
+			RETURNING pr_id, reviewer_id, verdict, comment, submitted_at
+		), logged AS (
+			INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+			SELECT pr_id, 'VERDICT', reviewer_id,
+			       (SELECT to_jsonb(previous) FROM previous),
+			       jsonb_build_object('verdict', verdict, 'comment', comment, 'submitted_at', submitted_at)
+			FROM stored
+		)
+		SELECT pr_id, reviewer_id, verdict, comment, submitted_at
+		FROM stored
+	`
+
+	var stored domain.Review
+	if err := r.conn(ctx).QueryRow(
+		ctx,
+		query,
+		review.PullRequestID,
+		review.ReviewerID,
+		review.Verdict,
+		review.Comment,
+	).Scan(
+		&stored.PullRequestID,
+		&stored.ReviewerID,
+		&stored.Verdict,
+		&stored.Comment,
+		&stored.SubmittedAt,
+	); err != nil {
+		return domain.Review{}, errutils.Wrap("failed to submit review", err)
+	}
+
+	return stored, nil
+}
+
+// GetReviews returns verdicts of the reviewers currently assigned to the PR.
+// Verdicts left by removed or replaced reviewers are ignored, as are verdicts
+// submitted before the reviewer's current assignment.
+func (r *PullRequestsRepo) GetReviews(ctx context.Context, prID string) ([]domain.Review, error) {
+	query := `
+		SELECT v.pr_id, v.reviewer_id, v.verdict, v.comment, v.submitted_at
+		FROM pr_reviews v
+		JOIN pr_reviewers r ON r.pr_id = v.pr_id AND r.reviewer_id = v.reviewer_id
+		WHERE v.pr_id = $1 AND v.submitted_at >= r.assigned_at
+		ORDER BY v.submitted_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get reviews", err)
+	}
+	defer rows.Close()
+
+	var reviews []domain.Review
+	for rows.Next() {
+		var review domain.Review
+		if err := rows.Scan(
+			&review.PullRequestID,
+			&review.ReviewerID,
+			&review.Verdict,
+			&review.Comment,
+			&review.SubmittedAt,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan review", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating reviews", err)
+	}
+
+	return reviews, nil
+}
+
+func (r *PullRequestsRepo) GetPRsWhereUserIsReviewer(ctx context.Context, userID string, filter domain.ReviewFilter) ([]domain.ReviewAssignment, error) {
 	query := `
 		SELECT 
 			pr.id,
@@ -252,35 +626,250 @@ func (r *PullRequestsRepo) GetPRsWhereUserIsReviewer(ctx context.Context, userID
 			pr.author_id,
 			pr.status,
 			pr.created_at,
-			pr.merged_at
+			pr.merged_at,
+			r.assigned_at,
+			r.due_at,
+			EXISTS (
+				SELECT 1 FROM pr_reviews v
+				WHERE v.pr_id = r.pr_id AND v.reviewer_id = r.reviewer_id AND v.submitted_at >= r.assigned_at
+			)
 		FROM pull_requests pr
 		JOIN pr_reviewers r ON pr.id = r.pr_id
 		WHERE r.reviewer_id = $1
-		ORDER BY pr.created_at DESC
+		  AND ($2 = '' OR pr.status = $2)
+		  AND ($3::TIMESTAMPTZ IS NULL OR (pr.created_at, pr.id) < ($3, $4))
+		ORDER BY pr.created_at DESC, pr.id DESC
+		LIMIT NULLIF($5, 0)
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := r.conn(ctx).Query(ctx, query, userID, filter.Status, filter.AfterTime, filter.AfterID, filter.Limit)
 	if err != nil {
 		return nil, errutils.Wrap("failed to get PRs where user is reviewer", err)
 	}
 	defer rows.Close()
 
-	var prs []domain.PullRequest
+	var prs []domain.ReviewAssignment
 
 	for rows.Next() {
-		var pr domain.PullRequest
+		var item domain.ReviewAssignment
+
+		if err := rows.Scan(
+			&item.PullRequest.ID,
+			&item.PullRequest.Name,
+			&item.PullRequest.AuthorID,
+			&item.PullRequest.Status,
+			&item.PullRequest.CreatedAt,
+			&item.PullRequest.MergedAt,
+			&item.AssignedAt,
+			&item.DueAt,
+			&item.Reviewed,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan pr", err)
+		}
+
+		prs = append(prs, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("failed to iterate PR rows", err)
+	}
+
+	return prs, nil
+}
+
+func (r *PullRequestsRepo) PullRequestExists(ctx context.Context, ID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id=$1)`
+	var exists bool
+
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(&exists); err != nil {
+		return false, errutils.Wrap("failed to check existing PR", err)
+	}
+
+	return exists, nil
+}
 
+func (r *PullRequestsRepo) GetUnderStaffedPullRequests(ctx context.Context, teamName string) ([]domain.PullRequest, error) {
+	query := `
+		SELECT
+			pr.id,
+			pr.name,
+			pr.author_id,
+			pr.status,
+			pr.under_staffed,
+			pr.created_at,
+			pr.merged_at,
+			ARRAY(SELECT r.reviewer_id FROM pr_reviewers r WHERE r.pr_id = pr.id ORDER BY r.assigned_at)
+		FROM pull_requests pr
+		JOIN users a ON a.id = pr.author_id
+		JOIN teams t ON t.id = a.team_id
+		WHERE pr.under_staffed = TRUE
+		  AND pr.status = 'OPEN'
+		  AND ($1 = '' OR t.name = $1)
+		ORDER BY pr.created_at
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, teamName)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get under-staffed PRs", err)
+	}
+	defer rows.Close()
+
+	var prs []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
 		if err := rows.Scan(
 			&pr.ID,
 			&pr.Name,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.UnderStaffed,
 			&pr.CreatedAt,
 			&pr.MergedAt,
+			&pr.Reviewers,
 		); err != nil {
 			return nil, errutils.Wrap("failed to scan pr", err)
 		}
+		prs = append(prs, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("failed to iterate PR rows", err)
+	}
+
+	return prs, nil
+}
+
+// SetAutoMerge turns auto-merge on or off and records the change made by
+// actorID in the timeline.
+func (r *PullRequestsRepo) SetAutoMerge(ctx context.Context, ID string, enabled bool, actorID *string) error {
+	query := `
+		WITH old AS (
+			SELECT id, auto_merge
+			FROM pull_requests
+			WHERE id = $1
+			FOR UPDATE
+		), updated AS (
+			UPDATE pull_requests pr
+			SET auto_merge = $2
+			FROM old
+			WHERE pr.id = old.id
+			RETURNING pr.id, old.auto_merge
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+		SELECT id, 'AUTO_MERGE_CHANGED', $3, jsonb_build_object('auto_merge', auto_merge), jsonb_build_object('auto_merge', $2::BOOLEAN)
+		FROM updated
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, ID, enabled, actorID)
+	if err != nil {
+		return errutils.Wrap("failed to set auto-merge", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrPullRequestNotFound
+	}
+
+	return nil
+}
+
+// UpdateName renames the PR and records the change made by actorID in the
+// timeline.
+func (r *PullRequestsRepo) UpdateName(ctx context.Context, ID, name string, actorID *string) error {
+	query := `
+		WITH old AS (
+			SELECT id, name
+			FROM pull_requests
+			WHERE id = $1
+			FOR UPDATE
+		), updated AS (
+			UPDATE pull_requests pr
+			SET name = $2
+			FROM old
+			WHERE pr.id = old.id
+			RETURNING pr.id, old.name
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before, after)
+		SELECT id, 'RENAMED', $3, jsonb_build_object('name', name), jsonb_build_object('name', $2::TEXT)
+		FROM updated
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, ID, name, actorID)
+	if err != nil {
+		return errutils.Wrap("failed to rename pr", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrPullRequestNotFound
+	}
+
+	return nil
+}
+
+// ListPullRequests returns one page of PRs matching the filter, newest first.
+func (r *PullRequestsRepo) ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) ([]domain.PullRequest, error) {
+	query := `
+		SELECT
+			pr.id,
+			pr.name,
+			pr.author_id,
+			pr.status,
+			pr.under_staffed,
+			pr.created_at,
+			pr.merged_at,
+			pr.closed_at,
+			ARRAY(SELECT r.reviewer_id FROM pr_reviewers r WHERE r.pr_id = pr.id ORDER BY r.assigned_at)
+		FROM pull_requests pr
+		JOIN users a ON a.id = pr.author_id
+		JOIN teams t ON t.id = a.team_id
+		WHERE ($1 = '' OR pr.author_id = $1)
+		  AND ($2 = '' OR t.name = $2)
+		  AND ($3 = '' OR pr.status = $3)
+		  AND ($4 = '' OR EXISTS (
+		      SELECT 1 FROM pr_reviewers r WHERE r.pr_id = pr.id AND r.reviewer_id = $4
+		  ))
+		  AND ($5::TIMESTAMPTZ IS NULL OR pr.created_at >= $5)
+		  AND ($6::TIMESTAMPTZ IS NULL OR pr.created_at < $6)
+		  AND ($7::TIMESTAMPTZ IS NULL OR pr.merged_at >= $7)
+		  AND ($8::TIMESTAMPTZ IS NULL OR pr.merged_at < $8)
+		  AND ($9::TIMESTAMPTZ IS NULL OR (pr.created_at, pr.id) < ($9, $10))
+		ORDER BY pr.created_at DESC, pr.id DESC
+		LIMIT $11
+	`
 
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		query,
+		filter.AuthorID,
+		filter.TeamName,
+		filter.Status,
+		filter.ReviewerID,
+		filter.CreatedFrom,
+		filter.CreatedTo,
+		filter.MergedFrom,
+		filter.MergedTo,
+		filter.AfterTime,
+		filter.AfterID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, errutils.Wrap("failed to list PRs", err)
+	}
+	defer rows.Close()
+
+	var prs []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(
+			&pr.ID,
+			&pr.Name,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.UnderStaffed,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+			&pr.Reviewers,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan pr", err)
+		}
 		prs = append(prs, pr)
 	}
 
@@ -291,13 +880,469 @@ func (r *PullRequestsRepo) GetPRsWhereUserIsReviewer(ctx context.Context, userID
 	return prs, nil
 }
 
-func (r *PullRequestsRepo) PullRequestExists(ctx context.Context, ID string) (bool, error) {
-	const query = `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id=$1)`
-	var exists bool
+// GetOverdueReviews returns assignments on open PRs whose due time passed
+// before the reviewer submitted a verdict, most overdue first. The team is the
+// PR author's one, whose SLA applies.
+func (r *PullRequestsRepo) GetOverdueReviews(ctx context.Context, teamName, userID string) ([]domain.OverdueReview, error) {
+	query := `
+		SELECT pr.id, pr.name, t.name, r.reviewer_id, r.assigned_at, r.due_at
+		FROM pr_reviewers r
+		JOIN pull_requests pr ON pr.id = r.pr_id
+		JOIN users a ON a.id = pr.author_id
+		JOIN teams t ON t.id = a.team_id
+		WHERE pr.status = 'OPEN'
+		  AND r.due_at < NOW()
+		  AND NOT EXISTS (
+		      SELECT 1 FROM pr_reviews v
+		      WHERE v.pr_id = r.pr_id AND v.reviewer_id = r.reviewer_id AND v.submitted_at >= r.assigned_at
+		  )
+		  AND ($1 = '' OR t.name = $1)
+		  AND ($2 = '' OR r.reviewer_id = $2)
+		ORDER BY r.due_at, pr.id
+	`
 
-	if err := r.db.QueryRow(ctx, query, ID).Scan(&exists); err != nil {
-		return false, errutils.Wrap("failed to check existing PR", err)
+	rows, err := r.conn(ctx).Query(ctx, query, teamName, userID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to get overdue reviews", err)
 	}
+	defer rows.Close()
 
-	return exists, nil
+	var overdue []domain.OverdueReview
+	for rows.Next() {
+		var o domain.OverdueReview
+		if err := rows.Scan(&o.PullRequestID, &o.PullRequestName, &o.TeamName, &o.ReviewerID, &o.AssignedAt, &o.DueAt); err != nil {
+			return nil, errutils.Wrap("failed to scan overdue review", err)
+		}
+		overdue = append(overdue, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating overdue reviews", err)
+	}
+
+	return overdue, nil
+}
+
+const insertLabelsQuery = `
+	INSERT INTO pr_labels (pr_id, label)
+	SELECT $1, UNNEST($2::TEXT[])
+	ON CONFLICT DO NOTHING
+`
+
+func (r *PullRequestsRepo) GetLabels(ctx context.Context, prID string) ([]string, error) {
+	query := `SELECT ARRAY(SELECT label FROM pr_labels WHERE pr_id = $1 ORDER BY label)`
+
+	var labels []string
+	if err := r.conn(ctx).QueryRow(ctx, query, prID).Scan(&labels); err != nil {
+		return nil, errutils.Wrap("failed to get labels", err)
+	}
+
+	return labels, nil
+}
+
+// AddLabels attaches labels to the PR on behalf of actorID. Labels it already
+// has are skipped and left out of the timeline.
+func (r *PullRequestsRepo) AddLabels(ctx context.Context, prID string, labels []string, actorID *string) error {
+	query := `
+		WITH added AS (
+			INSERT INTO pr_labels (pr_id, label)
+			SELECT $1, UNNEST($2::TEXT[])
+			ON CONFLICT DO NOTHING
+			RETURNING label
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, after)
+		SELECT $1, 'LABELS_ADDED', $3, jsonb_build_object('labels', ARRAY_AGG(label ORDER BY label))
+		FROM added
+		HAVING COUNT(*) > 0
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, labels, actorID); err != nil {
+		return errutils.Wrap("failed to add labels", err)
+	}
+
+	return nil
+}
+
+// RemoveLabels detaches labels from the PR on behalf of actorID. Only labels
+// the PR had are recorded in the timeline.
+func (r *PullRequestsRepo) RemoveLabels(ctx context.Context, prID string, labels []string, actorID *string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM pr_labels
+			WHERE pr_id = $1 AND label = ANY($2)
+			RETURNING label
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before)
+		SELECT $1, 'LABELS_REMOVED', $3, jsonb_build_object('labels', ARRAY_AGG(label ORDER BY label))
+		FROM removed
+		HAVING COUNT(*) > 0
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, labels, actorID); err != nil {
+		return errutils.Wrap("failed to remove labels", err)
+	}
+
+	return nil
+}
+
+// dependencyLockKey serializes changes of the dependency graph, so concurrent
+// writers can't close a cycle between them.
+const dependencyLockKey = 7_202_116
+
+// AddParents makes the PR depend on parentIDs on behalf of actorID and records
+// the new parents in the timeline. It fails with ErrPullRequestNotFound if a
+// parent doesn't exist and with ErrDependencyCycle if the PR is already an
+// ancestor of a parent.
+func (r *PullRequestsRepo) AddParents(ctx context.Context, prID string, parentIDs []string, actorID *string) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, dependencyLockKey); err != nil {
+		return errutils.Wrap("failed to lock dependencies", err)
+	}
+
+	var found int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM pull_requests WHERE id = ANY($1)`, parentIDs).Scan(&found); err != nil {
+		return errutils.Wrap("failed to check parents", err)
+	}
+	if found != len(parentIDs) {
+		return ErrPullRequestNotFound
+	}
+
+	query := `
+		WITH RECURSIVE ancestors(id) AS (
+		    SELECT UNNEST($2::TEXT[])
+		    UNION
+		    SELECT d.parent_id
+		    FROM pr_dependencies d
+		    JOIN ancestors a ON a.id = d.pr_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1)
+	`
+	var cycle bool
+	if err := tx.QueryRow(ctx, query, prID, parentIDs).Scan(&cycle); err != nil {
+		return errutils.Wrap("failed to check dependency cycle", err)
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	query = `
+		WITH added AS (
+			INSERT INTO pr_dependencies (pr_id, parent_id)
+			SELECT $1, UNNEST($2::TEXT[])
+			ON CONFLICT DO NOTHING
+			RETURNING parent_id
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, after)
+		SELECT $1, 'PARENTS_ADDED', $3, jsonb_build_object('parent_ids', ARRAY_AGG(parent_id ORDER BY parent_id))
+		FROM added
+		HAVING COUNT(*) > 0
+	`
+	if _, err := tx.Exec(ctx, query, prID, parentIDs, actorID); err != nil {
+		return errutils.Wrap("failed to insert dependencies", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// RemoveParents drops dependencies of the PR on behalf of actorID. Only the
+// parents the PR had are recorded in the timeline.
+func (r *PullRequestsRepo) RemoveParents(ctx context.Context, prID string, parentIDs []string, actorID *string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM pr_dependencies
+			WHERE pr_id = $1 AND parent_id = ANY($2)
+			RETURNING parent_id
+		)
+		INSERT INTO pr_events (pr_id, kind, actor_id, before)
+		SELECT $1, 'PARENTS_REMOVED', $3, jsonb_build_object('parent_ids', ARRAY_AGG(parent_id ORDER BY parent_id))
+		FROM removed
+		HAVING COUNT(*) > 0
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, parentIDs, actorID); err != nil {
+		return errutils.Wrap("failed to remove dependencies", err)
+	}
+
+	return nil
+}
+
+func (r *PullRequestsRepo) GetChildren(ctx context.Context, prID string) ([]string, error) {
+	query := `SELECT ARRAY(SELECT pr_id FROM pr_dependencies WHERE parent_id = $1 ORDER BY pr_id)`
+
+	var children []string
+	if err := r.conn(ctx).QueryRow(ctx, query, prID).Scan(&children); err != nil {
+		return nil, errutils.Wrap("failed to get children", err)
+	}
+
+	return children, nil
+}
+
+func (r *PullRequestsRepo) GetUnmergedParents(ctx context.Context, prID string) ([]string, error) {
+	query := `
+		SELECT ARRAY(
+		    SELECT d.parent_id
+		    FROM pr_dependencies d
+		    JOIN pull_requests pr ON pr.id = d.parent_id
+		    WHERE d.pr_id = $1 AND pr.status <> 'MERGED'
+		    ORDER BY d.parent_id
+		)
+	`
+
+	var parents []string
+	if err := r.conn(ctx).QueryRow(ctx, query, prID).Scan(&parents); err != nil {
+		return nil, errutils.Wrap("failed to get unmerged parents", err)
+	}
+
+	return parents, nil
+}
+
+// GetStack returns every PR connected to prID through dependencies in either
+// direction, oldest first.
+func (r *PullRequestsRepo) GetStack(ctx context.Context, prID string) ([]domain.PullRequest, error) {
+	query := `
+		WITH RECURSIVE stack(id) AS (
+		    SELECT $1::TEXT
+		    UNION
+		    SELECT CASE WHEN d.pr_id = s.id THEN d.parent_id ELSE d.pr_id END
+		    FROM pr_dependencies d
+		    JOIN stack s ON s.id IN (d.pr_id, d.parent_id)
+		)
+		SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at,
+		       ARRAY(SELECT d.parent_id FROM pr_dependencies d WHERE d.pr_id = pr.id ORDER BY d.parent_id)
+		FROM pull_requests pr
+		JOIN stack s ON s.id = pr.id
+		ORDER BY pr.created_at, pr.id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query stack", err)
+	}
+	defer rows.Close()
+
+	var stack []domain.PullRequest
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ParentIDs); err != nil {
+			return nil, errutils.Wrap("failed to scan stack pr", err)
+		}
+		stack = append(stack, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating stack", err)
+	}
+
+	return stack, nil
+}
+
+// ClaimReminders marks assignments older than assignedBefore as reminded and
+// returns them. The update is conditional, so concurrent callers never claim
+// the same assignment twice.
+func (r *PullRequestsRepo) ClaimReminders(ctx context.Context, assignedBefore time.Time) ([]domain.StaleReview, error) {
+	query := `
+		UPDATE pr_reviewers r
+		SET reminded_at = NOW()
+		FROM pull_requests pr
+		WHERE pr.id = r.pr_id
+		  AND pr.status = 'OPEN'
+		  AND r.reminded_at IS NULL
+		  AND r.assigned_at < $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM pr_reviews v
+		      WHERE v.pr_id = r.pr_id AND v.reviewer_id = r.reviewer_id AND v.submitted_at >= r.assigned_at
+		  )
+		RETURNING r.pr_id, pr.name, pr.author_id, r.reviewer_id, r.assigned_at
+	`
+
+	return r.claimStaleReviews(ctx, query, assignedBefore)
+}
+
+// ClaimEscalations marks reminded assignments older than assignedBefore as
+// escalated and returns them. Like ClaimReminders, every assignment is
+// claimed once.
+func (r *PullRequestsRepo) ClaimEscalations(ctx context.Context, assignedBefore time.Time) ([]domain.StaleReview, error) {
+	query := `
+		UPDATE pr_reviewers r
+		SET escalated_at = NOW()
+		FROM pull_requests pr
+		WHERE pr.id = r.pr_id
+		  AND pr.status = 'OPEN'
+		  AND r.reminded_at IS NOT NULL
+		  AND r.escalated_at IS NULL
+		  AND r.assigned_at < $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM pr_reviews v
+		      WHERE v.pr_id = r.pr_id AND v.reviewer_id = r.reviewer_id AND v.submitted_at >= r.assigned_at
+		  )
+		RETURNING r.pr_id, pr.name, pr.author_id, r.reviewer_id, r.assigned_at
+	`
+
+	return r.claimStaleReviews(ctx, query, assignedBefore)
+}
+
+// ReleaseEscalation drops the escalation claim of the assignment, so the next
+// ClaimEscalations picks it up again.
+func (r *PullRequestsRepo) ReleaseEscalation(ctx context.Context, prID, reviewerID string) error {
+	query := `
+		UPDATE pr_reviewers
+		SET escalated_at = NULL
+		WHERE pr_id = $1 AND reviewer_id = $2
+	`
+
+	if _, err := r.conn(ctx).Exec(ctx, query, prID, reviewerID); err != nil {
+		return errutils.Wrap("failed to release escalation", err)
+	}
+
+	return nil
+}
+
+func (r *PullRequestsRepo) claimStaleReviews(ctx context.Context, query string, assignedBefore time.Time) ([]domain.StaleReview, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, assignedBefore)
+	if err != nil {
+		return nil, errutils.Wrap("failed to claim stale reviews", err)
+	}
+	defer rows.Close()
+
+	var stale []domain.StaleReview
+	for rows.Next() {
+		var s domain.StaleReview
+		if err := rows.Scan(&s.PullRequestID, &s.PullRequestName, &s.AuthorID, &s.ReviewerID, &s.AssignedAt); err != nil {
+			return nil, errutils.Wrap("failed to scan stale review", err)
+		}
+		stale = append(stale, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating stale reviews", err)
+	}
+
+	return stale, nil
+}
+
+func filesOrEmpty(files []string) []string {
+	if files == nil {
+		return []string{}
+	}
+	return files
+}
+
+// GetEvents returns the timeline of the PR, oldest event first.
+func (r *PullRequestsRepo) GetEvents(ctx context.Context, prID string) ([]domain.Event, error) {
+	query := `
+		SELECT id, pr_id, kind, actor_id, before, after, created_at
+		FROM pr_events
+		WHERE pr_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query events", err)
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var (
+			e             domain.Event
+			before, after []byte
+		)
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.Kind, &e.ActorID, &before, &after, &e.CreatedAt); err != nil {
+			return nil, errutils.Wrap("failed to scan event", err)
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating events", err)
+	}
+
+	return events, nil
+}
+
+// GetReviewersAt returns the assignment periods of the PR that cover at.
+func (r *PullRequestsRepo) GetReviewersAt(ctx context.Context, prID string, at time.Time) ([]domain.AssignmentPeriod, error) {
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, h.reviewer_id, h.valid_from, h.valid_to
+		FROM pr_assignment_history h
+		JOIN pull_requests pr ON pr.id = h.pr_id
+		WHERE h.pr_id = $1
+		  AND h.valid_from <= $2
+		  AND (h.valid_to IS NULL OR h.valid_to > $2)
+		ORDER BY h.valid_from, h.reviewer_id
+	`
+
+	return r.queryAssignmentPeriods(ctx, query, prID, at)
+}
+
+// GetReviewQueueAt returns the assignment periods of the user that cover at,
+// skipping PRs that were already merged by then or closed at that moment. A
+// reopen clears closed_at, so earlier closed periods come from the status
+// changes in the timeline.
+func (r *PullRequestsRepo) GetReviewQueueAt(ctx context.Context, userID string, at time.Time) ([]domain.AssignmentPeriod, error) {
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, h.reviewer_id, h.valid_from, h.valid_to
+		FROM pr_assignment_history h
+		JOIN pull_requests pr ON pr.id = h.pr_id
+		WHERE h.reviewer_id = $1
+		  AND h.valid_from <= $2
+		  AND (h.valid_to IS NULL OR h.valid_to > $2)
+		  AND (pr.merged_at IS NULL OR pr.merged_at > $2)
+		  AND (pr.closed_at IS NULL OR pr.closed_at > $2)
+		  AND COALESCE((
+			SELECT e.after->>'status'
+			FROM pr_events e
+			WHERE e.pr_id = pr.id
+			  AND e.kind = 'STATUS_CHANGED'
+			  AND e.created_at <= $2
+			ORDER BY e.created_at DESC, e.id DESC
+			LIMIT 1
+		  ), '') <> 'CLOSED'
+		ORDER BY h.valid_from, pr.id
+	`
+
+	return r.queryAssignmentPeriods(ctx, query, userID, at)
+}
+
+func (r *PullRequestsRepo) queryAssignmentPeriods(ctx context.Context, query string, args ...any) ([]domain.AssignmentPeriod, error) {
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query assignment history", err)
+	}
+	defer rows.Close()
+
+	var periods []domain.AssignmentPeriod
+	for rows.Next() {
+		var p domain.AssignmentPeriod
+		if err := rows.Scan(
+			&p.PullRequest.ID,
+			&p.PullRequest.Name,
+			&p.PullRequest.AuthorID,
+			&p.ReviewerID,
+			&p.ValidFrom,
+			&p.ValidTo,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan assignment period", err)
+		}
+		periods = append(periods, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("error iterating assignment history", err)
+	}
+
+	return periods, nil
 }
@@ -8,15 +8,38 @@ import (
 	"github.com/ilam072/avito-backend-internship/internal/response"
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
 	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/pagination"
 	"github.com/rs/zerolog/log"
 	"net/http"
 )
 
 type PullRequest interface {
 	CreatePullRequest(ctx context.Context, pr dto.CreatePullRequest) (dto.GetPullRequest, error)
-	MergePullRequest(ctx context.Context, ID string) (dto.PRResponse, error)
-	ReassignReviewer(ctx context.Context, prID string, userID string) (dto.ReassignResponse, error)
-	GetPRsWhereUserIsReviewer(ctx context.Context, userID string) (dto.GetReviewResponse, error)
+	MergePullRequest(ctx context.Context, ID string, override bool, actorID string) (dto.PRResponse, error)
+	SubmitReview(ctx context.Context, prID, reviewerID, verdict, comment string) (dto.SubmitReviewResponse, error)
+	MarkReady(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error)
+	ClosePullRequest(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error)
+	ReopenPullRequest(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error)
+	GetPullRequest(ctx context.Context, prID string) (dto.GetPullRequest, error)
+	RenamePullRequest(ctx context.Context, prID, name, actorID string) (dto.GetPullRequest, error)
+	AddLabels(ctx context.Context, prID, actorID string, labels []string) (dto.GetPullRequest, error)
+	RemoveLabels(ctx context.Context, prID, actorID string, labels []string) (dto.GetPullRequest, error)
+	AddParents(ctx context.Context, prID, actorID string, parentIDs []string) (dto.GetPullRequest, error)
+	RemoveParents(ctx context.Context, prID, actorID string, parentIDs []string) (dto.GetPullRequest, error)
+	GetStack(ctx context.Context, prID string) (dto.StackResponse, error)
+	GetTimeline(ctx context.Context, prID string) (dto.TimelineResponse, error)
+	GetReviewersAsOf(ctx context.Context, req dto.ReviewersAsOfRequest) (dto.ReviewersAsOfResponse, error)
+	GetReviewQueueAsOf(ctx context.Context, req dto.ReviewQueueAsOfRequest) (dto.ReviewQueueAsOfResponse, error)
+	SetAutoMerge(ctx context.Context, prID, actorID string, enabled bool) (dto.GetPullRequest, error)
+	ListPullRequests(ctx context.Context, req dto.ListPullRequestsRequest) (dto.PullRequestListResponse, error)
+	ReassignReviewer(ctx context.Context, prID string, userID string, actorID string) (dto.ReassignResponse, error)
+	AddReviewer(ctx context.Context, prID, userID, actorID string) (dto.GetPullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID, actorID string) (dto.GetPullRequest, error)
+	DeclineReview(ctx context.Context, prID, userID, reason string) (dto.DeclineResponse, error)
+	GetPRsWhereUserIsReviewer(ctx context.Context, req dto.GetReviewRequest) (dto.GetReviewResponse, error)
+	GetUnderStaffedPullRequests(ctx context.Context, teamName string) (dto.PullRequestsResponse, error)
+	GetOverdueReviews(ctx context.Context, teamName, userID string) (dto.OverdueReviewsResponse, error)
+	SuggestReviewers(ctx context.Context, prID, oldUserID string) (dto.SuggestReviewersResponse, error)
 }
 
 type Validator interface {
@@ -52,10 +75,22 @@ func (h *PullRequestHandler) CreatePullRequest(c *gin.Context) {
 			response.Conflict(c, "PR_EXISTS", "PR id already exists")
 			return
 		}
-		if errors.Is(err, domain.ErrUserNotFound) {
+		if errors.Is(err, domain.ErrInvalidLabel) {
+			response.BadRequest(c, "invalid label")
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
 			response.NotFound(c)
 			return
 		}
+		if errors.Is(err, domain.ErrNotEnoughSeniors) {
+			response.Conflict(c, "NOT_ENOUGH_SENIOR_REVIEWERS", "not enough available senior reviewers for the team policy")
+			return
+		}
+		if errors.Is(err, domain.ErrNotEnoughReviewers) {
+			response.Conflict(c, "NOT_ENOUGH_REVIEWERS", "not enough active reviewers in team")
+			return
+		}
 		log.Logger.Error().Err(err).Any("pr", pr).Msg("failed to create pull request")
 		response.InternalServerError(c)
 		return
@@ -64,6 +99,307 @@ func (h *PullRequestHandler) CreatePullRequest(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"pr": prResp})
 }
 
+func (h *PullRequestHandler) GetPullRequest(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		log.Logger.Warn().Msg("empty pull request id")
+		response.BadRequest(c, "invalid 'pull_request_id' query parameter")
+		return
+	}
+
+	prResp, err := h.pr.GetPullRequest(c.Request.Context(), prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Str("pull_request_id", prID).Msg("failed to get pull request")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) Rename(c *gin.Context) {
+	var req dto.RenamePullRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to rename pr req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.RenamePullRequest(c.Request.Context(), req.ID, req.Name, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot rename merged PR")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to rename pull request")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) AddLabels(c *gin.Context) {
+	h.changeLabels(c, h.pr.AddLabels)
+}
+
+func (h *PullRequestHandler) RemoveLabels(c *gin.Context) {
+	h.changeLabels(c, h.pr.RemoveLabels)
+}
+
+func (h *PullRequestHandler) changeLabels(c *gin.Context, change func(ctx context.Context, prID, actorID string, labels []string) (dto.GetPullRequest, error)) {
+	var req dto.LabelsRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to pr labels req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := change(c.Request.Context(), req.ID, req.ActorID, req.Labels)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLabel) {
+			response.BadRequest(c, "invalid label")
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot change labels of merged PR")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to change pull request labels")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) AddParents(c *gin.Context) {
+	h.changeParents(c, h.pr.AddParents)
+}
+
+func (h *PullRequestHandler) RemoveParents(c *gin.Context) {
+	h.changeParents(c, h.pr.RemoveParents)
+}
+
+func (h *PullRequestHandler) changeParents(c *gin.Context, change func(ctx context.Context, prID, actorID string, parentIDs []string) (dto.GetPullRequest, error)) {
+	var req dto.ParentsRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to pr parents req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := change(c.Request.Context(), req.ID, req.ActorID, req.ParentIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot change dependencies of merged PR")
+			return
+		}
+		if errors.Is(err, domain.ErrDependencyCycle) {
+			response.Conflict(c, "DEPENDENCY_CYCLE", "dependencies would form a cycle")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to change pull request parents")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) GetStack(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		log.Logger.Warn().Msg("empty pull request id")
+		response.BadRequest(c, "invalid 'pull_request_id' query parameter")
+		return
+	}
+
+	stack, err := h.pr.GetStack(c.Request.Context(), prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Str("pull_request_id", prID).Msg("failed to get pull request stack")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, stack)
+}
+
+func (h *PullRequestHandler) GetTimeline(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		log.Logger.Warn().Msg("empty pull request id")
+		response.BadRequest(c, "invalid 'pull_request_id' query parameter")
+		return
+	}
+
+	timeline, err := h.pr.GetTimeline(c.Request.Context(), prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Str("pull_request_id", prID).Msg("failed to get pull request timeline")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func (h *PullRequestHandler) GetReviewersAsOf(c *gin.Context) {
+	var req dto.ReviewersAsOfRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind reviewers as of query")
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	reviewersResp, err := h.pr.GetReviewersAsOf(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to get reviewers as of time")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewersResp)
+}
+
+func (h *PullRequestHandler) GetReviewQueueAsOf(c *gin.Context) {
+	var req dto.ReviewQueueAsOfRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind review queue as of query")
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	queueResp, err := h.pr.GetReviewQueueAsOf(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to get review queue as of time")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, queueResp)
+}
+
+func (h *PullRequestHandler) SetAutoMerge(c *gin.Context) {
+	var req dto.AutoMergeRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to set auto-merge req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.SetAutoMerge(c.Request.Context(), req.ID, req.ActorID, req.Enabled)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set auto-merge")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) List(c *gin.Context) {
+	var req dto.ListPullRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind list pr query")
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	listResp, err := h.pr.ListPullRequests(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(c, "invalid 'cursor' query parameter")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to list pull requests")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, listResp)
+}
+
 func (h *PullRequestHandler) MergePullRequest(c *gin.Context) {
 	var req dto.MergePRRequest
 	if err := c.BindJSON(&req); err != nil {
@@ -78,12 +414,27 @@ func (h *PullRequestHandler) MergePullRequest(c *gin.Context) {
 		return
 	}
 
-	prResp, err := h.pr.MergePullRequest(c.Request.Context(), req.ID)
+	prResp, err := h.pr.MergePullRequest(c.Request.Context(), req.ID, req.AdminOverride, req.ActorID)
 	if err != nil {
-		if errors.Is(err, domain.ErrPullRequestNotFound) {
+		if errors.Is(err, domain.ErrPullRequestNotFound) || errors.Is(err, domain.ErrUserNotFound) {
 			response.NotFound(c)
 			return
 		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrParentsNotMerged) {
+			response.Conflict(c, "PARENTS_NOT_MERGED", "cannot merge PR before the PRs it depends on")
+			return
+		}
+		if errors.Is(err, domain.ErrChangesRequested) {
+			response.Conflict(c, "CHANGES_REQUESTED", "reviewers requested changes")
+			return
+		}
+		if errors.Is(err, domain.ErrNotApproved) {
+			response.Conflict(c, "NOT_APPROVED", "PR has not enough approvals")
+			return
+		}
 		log.Logger.Error().Err(err).Any("req", req).Msg("failed to merge pull request")
 		response.InternalServerError(c)
 		return
@@ -92,6 +443,115 @@ func (h *PullRequestHandler) MergePullRequest(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"pr": prResp})
 }
 
+func (h *PullRequestHandler) MarkReady(c *gin.Context) {
+	var req dto.PullRequestIDRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to mark ready req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.MarkReady(c.Request.Context(), req.ID, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrNotEnoughSeniors) {
+			response.Conflict(c, "NOT_ENOUGH_SENIOR_REVIEWERS", "not enough available senior reviewers for the team policy")
+			return
+		}
+		if errors.Is(err, domain.ErrNotEnoughReviewers) {
+			response.Conflict(c, "NOT_ENOUGH_REVIEWERS", "not enough active reviewers in team")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to mark pull request ready")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) Close(c *gin.Context) {
+	var req dto.PullRequestIDRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to close pr req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.ClosePullRequest(c.Request.Context(), req.ID, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to close pull request")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) Reopen(c *gin.Context) {
+	var req dto.PullRequestIDRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to reopen pr req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.ReopenPullRequest(c.Request.Context(), req.ID, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrNotEnoughSeniors) {
+			response.Conflict(c, "NOT_ENOUGH_SENIOR_REVIEWERS", "not enough available senior reviewers for the team policy")
+			return
+		}
+		if errors.Is(err, domain.ErrNotEnoughReviewers) {
+			response.Conflict(c, "NOT_ENOUGH_REVIEWERS", "not enough active reviewers in team")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to reopen pull request")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
 func (h *PullRequestHandler) Reassign(c *gin.Context) {
 	var req dto.ReassignRequest
 	if err := c.BindJSON(&req); err != nil {
@@ -106,7 +566,7 @@ func (h *PullRequestHandler) Reassign(c *gin.Context) {
 		return
 	}
 
-	prResp, err := h.pr.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.UserID)
+	prResp, err := h.pr.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.UserID, req.ActorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrPullRequestNotFound) {
 			response.NotFound(c)
@@ -120,12 +580,15 @@ func (h *PullRequestHandler) Reassign(c *gin.Context) {
 			response.Conflict(c, "PR_MERGED", "cannot reassign on merged PR")
 			return
 		}
+		if statusConflict(c, err) {
+			return
+		}
 		if errors.Is(err, domain.ErrUserNotAssignedForPR) {
 			response.Conflict(c, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
 			return
 		}
 		if errors.Is(err, domain.ErrNoCandidate) {
-			response.Conflict(c, "NO_CANDIDATE", "no active replacement candidate in team")
+			response.Conflict(c, "NO_CANDIDATE", "no active replacement candidate in team or its fallback teams")
 			return
 		}
 		log.Logger.Error().Err(err).Any("req", req).Msg("failed to reassign reviewer")
@@ -136,19 +599,285 @@ func (h *PullRequestHandler) Reassign(c *gin.Context) {
 	c.JSON(http.StatusOK, prResp)
 }
 
+func (h *PullRequestHandler) AddReviewer(c *gin.Context) {
+	var req dto.ReviewerRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to add reviewer req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.AddReviewer(c.Request.Context(), req.PullRequestID, req.UserID, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot add reviewer on merged PR")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrReviewerAssigned) {
+			response.Conflict(c, "ALREADY_ASSIGNED", "reviewer is already assigned to this PR")
+			return
+		}
+		if errors.Is(err, domain.ErrReviewerNotEligible) {
+			response.Conflict(c, "NOT_ELIGIBLE", "user must be an available member of the author's team or its fallback teams and not the author")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to add reviewer")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) RemoveReviewer(c *gin.Context) {
+	var req dto.ReviewerRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to remove reviewer req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.RemoveReviewer(c.Request.Context(), req.PullRequestID, req.UserID, req.ActorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot remove reviewer on merged PR")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotAssignedForPR) {
+			response.Conflict(c, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to remove reviewer")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": prResp})
+}
+
+func (h *PullRequestHandler) Decline(c *gin.Context) {
+	var req dto.DeclineRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to decline req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prResp, err := h.pr.DeclineReview(c.Request.Context(), req.PullRequestID, req.UserID, req.Reason)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot decline review on merged PR")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotAssignedForPR) {
+			response.Conflict(c, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to decline review")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, prResp)
+}
+
+func (h *PullRequestHandler) SubmitReview(c *gin.Context) {
+	var req dto.SubmitReviewRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json to submit review req")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	reviewResp, err := h.pr.SubmitReview(c.Request.Context(), req.PullRequestID, req.ReviewerID, req.Verdict, req.Comment)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot review merged PR")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotAssignedForPR) {
+			response.Conflict(c, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to submit review")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewResp)
+}
+
 func (h *PullRequestHandler) GetReview(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
+	var req dto.GetReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind get review query")
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	if req.UserID == "" {
 		log.Logger.Warn().Msg("empty user id")
 		response.BadRequest(c, "invalid 'user_id' query parameter")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	prsResp, err := h.pr.GetPRsWhereUserIsReviewer(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.BadRequest(c, "invalid 'cursor' query parameter")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to get prs by user id")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, prsResp)
+}
+
+func (h *PullRequestHandler) SuggestReviewers(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		log.Logger.Warn().Msg("empty pull request id")
+		response.BadRequest(c, "invalid 'pull_request_id' query parameter")
+		return
+	}
+	oldUserID := c.Query("old_user_id")
+
+	suggestResp, err := h.pr.SuggestReviewers(c.Request.Context(), prID, oldUserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPullRequestNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrPullRequestMerged) {
+			response.Conflict(c, "PR_MERGED", "cannot suggest reviewers for merged PR")
+			return
+		}
+		if statusConflict(c, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotAssignedForPR) {
+			response.Conflict(c, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
+			return
+		}
+		log.Logger.Error().Err(err).Str("pull_request_id", prID).Msg("failed to suggest reviewers")
+		response.InternalServerError(c)
+		return
 	}
 
-	prsResp, err := h.pr.GetPRsWhereUserIsReviewer(c.Request.Context(), userID)
+	c.JSON(http.StatusOK, suggestResp)
+}
+
+func (h *PullRequestHandler) GetUnderStaffed(c *gin.Context) {
+	teamName := c.Query("team_name")
+
+	prsResp, err := h.pr.GetUnderStaffedPullRequests(c.Request.Context(), teamName)
 	if err != nil {
-		log.Logger.Error().Err(err).Any("user_id", userID).Msg("failed to get prs by user id")
+		log.Logger.Error().Err(err).Any("team_name", teamName).Msg("failed to get under-staffed prs")
 		response.InternalServerError(c)
 		return
 	}
 
 	c.JSON(http.StatusOK, prsResp)
 }
+
+func (h *PullRequestHandler) GetOverdue(c *gin.Context) {
+	teamName := c.Query("team_name")
+	userID := c.Query("user_id")
+
+	overdueResp, err := h.pr.GetOverdueReviews(c.Request.Context(), teamName, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Str("team_name", teamName).Str("user_id", userID).Msg("failed to get overdue reviews")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, overdueResp)
+}
+
+// statusConflict responds with the conflict code of the PR status that doesn't
+// allow the requested action.
+func statusConflict(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrPullRequestDraft):
+		response.Conflict(c, "PR_DRAFT", "action is not allowed for draft PR")
+	case errors.Is(err, domain.ErrPullRequestOpen):
+		response.Conflict(c, "PR_OPEN", "action is not allowed for open PR")
+	case errors.Is(err, domain.ErrPullRequestMerged):
+		response.Conflict(c, "PR_MERGED", "action is not allowed for merged PR")
+	case errors.Is(err, domain.ErrPullRequestClosed):
+		response.Conflict(c, "PR_CLOSED", "action is not allowed for closed PR")
+	case errors.Is(err, domain.ErrStatusChanged):
+		response.Conflict(c, "STATUS_CHANGED", "PR status changed, retry the request")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/glob"
+	"regexp"
+	"slices"
+	"time"
+)
+
+type ownerMatch struct {
+	rule  domain.OwnershipRule
+	files []string
+}
+
+type rankedCandidate struct {
+	domain.Candidate
+	fallbackPos int // index in TeamSettings.FallbackTeamIDs, -1 for the team itself
+}
+
+type reviewerPick struct {
+	reviewers   []domain.Candidate
+	assignments []domain.Assignment
+	matches     []ownerMatch
+	policies    []domain.LabelPolicy
+	unmet       []domain.LabelPolicy
+}
+
+// underStaffed reports whether the pick falls short of the reviewer count or
+// misses a group a label policy requires.
+func (p *reviewerPick) underStaffed(settings domain.TeamSettings) bool {
+	return len(p.reviewers) < settings.ReviewerCount || len(p.unmet) > 0
+}
+
+func (p *reviewerPick) add(reviewers []domain.Candidate, assignments []domain.Assignment) {
+	p.reviewers = append(p.reviewers, reviewers...)
+	p.assignments = append(p.assignments, assignments...)
+}
+
+// ownerAssignments explains picks made among code owners, naming the first
+// matched rule that owns each reviewer.
+func (p *PullRequest) ownerAssignments(matches []ownerMatch, picked []domain.Candidate, poolSize int, source string) []domain.Assignment {
+	assignments := make([]domain.Assignment, len(picked))
+	for i, c := range picked {
+		assignments[i] = p.explain(c, poolSize, source, false)
+		for _, m := range matches {
+			if m.rule.Owns(c) {
+				assignments[i].Factors.Rule = m.rule.Pattern
+				break
+			}
+		}
+	}
+	return assignments
+}
+
+// matchOwnershipRules assigns every file to the last rule matching it, as
+// CODEOWNERS does, and returns the matched rules in their original order.
+func matchOwnershipRules(rules []domain.OwnershipRule, files []string) []ownerMatch {
+	compiled := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		if re, err := glob.Compile(rule.Pattern); err == nil {
+			compiled[i] = re
+		}
+	}
+
+	filesByRule := make(map[int][]string)
+	for _, file := range files {
+		path := glob.CleanPath(file)
+		for i := len(rules) - 1; i >= 0; i-- {
+			if compiled[i] != nil && compiled[i].MatchString(path) {
+				filesByRule[i] = append(filesByRule[i], file)
+				break
+			}
+		}
+	}
+
+	var matches []ownerMatch
+	for i, rule := range rules {
+		if matched, ok := filesByRule[i]; ok {
+			matches = append(matches, ownerMatch{rule: rule, files: matched})
+		}
+	}
+
+	return matches
+}
+
+// pickReviewers fills the team quota for a new pull request. Members of the
+// groups required by label policies go first, then owners of the touched
+// paths, one per matched rule. Missing seniors are taken next,
+// preferably from the owners, even if that exceeds the quota. The rest comes
+// from other owners, the author's team and finally its fallback teams.
+func (p *PullRequest) pickReviewers(ctx context.Context, author domain.User, settings domain.TeamSettings, files []string, policies []domain.LabelPolicy) (reviewerPick, error) {
+	pick := reviewerPick{policies: policies}
+	excluded := func() []string {
+		return append([]string{author.ID}, reviewerIDs(pick.reviewers)...)
+	}
+
+	for _, policy := range policies {
+		if !policy.RequiresGroup() || slices.ContainsFunc(pick.reviewers, policy.InGroup) {
+			continue
+		}
+
+		picked, poolSize, err := p.pick(ctx, settings.TeamID, domain.CandidateFilter{
+			TeamIDs:    policy.GroupTeamIDs,
+			UserIDs:    policy.GroupUserIDs,
+			ExcludeIDs: excluded(),
+		}, 1)
+		if err != nil {
+			return reviewerPick{}, err
+		}
+
+		assignments := make([]domain.Assignment, len(picked))
+		for i, c := range picked {
+			assignments[i] = p.explain(c, poolSize, domain.SourceLabel, false)
+			assignments[i].Factors.Rule = policy.Label
+		}
+		pick.add(picked, assignments)
+	}
+
+	if len(files) > 0 {
+		rules, err := p.teamRepo.GetOwnershipRules(ctx, author.TeamID)
+		if err != nil {
+			return reviewerPick{}, err
+		}
+		pick.matches = matchOwnershipRules(rules, files)
+	}
+
+	var owners domain.CandidateFilter
+	for _, m := range pick.matches {
+		owners.TeamIDs = append(owners.TeamIDs, m.rule.OwnerTeamIDs...)
+		owners.UserIDs = append(owners.UserIDs, m.rule.OwnerUserIDs...)
+
+		if len(pick.reviewers) >= settings.ReviewerCount || slices.ContainsFunc(pick.reviewers, m.rule.Owns) {
+			continue
+		}
+
+		picked, poolSize, err := p.pick(ctx, settings.TeamID, domain.CandidateFilter{
+			TeamIDs:    m.rule.OwnerTeamIDs,
+			UserIDs:    m.rule.OwnerUserIDs,
+			ExcludeIDs: excluded(),
+		}, 1)
+		if err != nil {
+			return reviewerPick{}, err
+		}
+		pick.add(picked, p.ownerAssignments([]ownerMatch{m}, picked, poolSize, domain.SourceOwnership))
+	}
+
+	if needed := settings.SeniorReviewers - countSeniors(settings, pick.reviewers); needed > 0 {
+		levels := domain.LevelsFrom(settings.SeniorLevel)
+
+		if len(pick.matches) > 0 {
+			seniorOwners := owners
+			seniorOwners.Levels = levels
+			seniorOwners.ExcludeIDs = excluded()
+			picked, poolSize, err := p.pick(ctx, settings.TeamID, seniorOwners, needed)
+			if err != nil {
+				return reviewerPick{}, err
+			}
+			pick.add(picked, p.ownerAssignments(pick.matches, picked, poolSize, domain.SourceSeniority))
+			needed -= len(picked)
+		}
+
+		picked, assignments, err := p.pickFromTeams(ctx, settings, domain.CandidateFilter{
+			ExcludeIDs: excluded(),
+			Levels:     levels,
+		}, needed, domain.SourceSeniority)
+		if err != nil {
+			return reviewerPick{}, err
+		}
+		pick.add(picked, assignments)
+	}
+
+	if len(pick.matches) > 0 {
+		owners.ExcludeIDs = excluded()
+		picked, poolSize, err := p.pick(ctx, settings.TeamID, owners, settings.ReviewerCount-len(pick.reviewers))
+		if err != nil {
+			return reviewerPick{}, err
+		}
+		pick.add(picked, p.ownerAssignments(pick.matches, picked, poolSize, domain.SourceOwnership))
+	}
+
+	picked, assignments, err := p.pickFromTeams(ctx, settings, domain.CandidateFilter{
+		ExcludeIDs: excluded(),
+	}, settings.ReviewerCount-len(pick.reviewers), domain.SourceTeam)
+	if err != nil {
+		return reviewerPick{}, err
+	}
+	pick.add(picked, assignments)
+	setDueAt(settings, pick.assignments)
+
+	return pick, nil
+}
+
+// pickFromTeams takes up to n reviewers matching filter from the team itself
+// and spills over into its fallback teams in the configured order. Every pick
+// is explained with source.
+func (p *PullRequest) pickFromTeams(ctx context.Context, settings domain.TeamSettings, filter domain.CandidateFilter, n int, source string) ([]domain.Candidate, []domain.Assignment, error) {
+	var (
+		picked      []domain.Candidate
+		assignments []domain.Assignment
+	)
+
+	teamIDs := append([]int{settings.TeamID}, settings.FallbackTeamIDs...)
+	for i, teamID := range teamIDs {
+		if len(picked) >= n {
+			break
+		}
+
+		got, poolSize, err := p.pick(ctx, settings.TeamID, domain.CandidateFilter{
+			TeamIDs:    []int{teamID},
+			ExcludeIDs: append(slices.Clone(filter.ExcludeIDs), reviewerIDs(picked)...),
+			Levels:     filter.Levels,
+		}, n-len(picked))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, c := range got {
+			assignments = append(assignments, p.explain(c, poolSize, source, i > 0))
+		}
+		picked = append(picked, got...)
+	}
+
+	return picked, assignments, nil
+}
+
+// rankFromTeams orders every candidate matching filter the way pickFromTeams
+// would try them: the team itself first, then its fallback teams in order.
+func (p *PullRequest) rankFromTeams(ctx context.Context, settings domain.TeamSettings, filter domain.CandidateFilter) ([]rankedCandidate, error) {
+	var ranked []rankedCandidate
+
+	teamIDs := append([]int{settings.TeamID}, settings.FallbackTeamIDs...)
+	for i, teamID := range teamIDs {
+		candidates, err := p.userRepo.GetReviewCandidates(ctx, domain.CandidateFilter{
+			TeamIDs:    []int{teamID},
+			ExcludeIDs: filter.ExcludeIDs,
+			Levels:     filter.Levels,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range p.selector.Rank(settings.TeamID, candidates) {
+			ranked = append(ranked, rankedCandidate{Candidate: c, fallbackPos: i - 1})
+		}
+	}
+
+	return ranked, nil
+}
+
+// manualAssignment checks a manually chosen reviewer against the same
+// candidate query automatic selection uses: the author's team or its fallback
+// teams, active, present, with spare capacity and not the author or a
+// decliner. The returned flag is false when the user is not eligible.
+func (p *PullRequest) manualAssignment(ctx context.Context, pr domain.PullRequest, settings domain.TeamSettings, userID string) (domain.Assignment, bool, error) {
+	decliners, err := p.prRepo.GetDecliners(ctx, pr.ID)
+	if err != nil {
+		return domain.Assignment{}, false, err
+	}
+
+	candidates, err := p.userRepo.GetReviewCandidates(ctx, domain.CandidateFilter{
+		TeamIDs:    append([]int{settings.TeamID}, settings.FallbackTeamIDs...),
+		ExcludeIDs: append(decliners, pr.AuthorID),
+	})
+	if err != nil {
+		return domain.Assignment{}, false, err
+	}
+
+	i := slices.IndexFunc(candidates, func(c domain.Candidate) bool {
+		return c.ID == userID
+	})
+	if i < 0 {
+		return domain.Assignment{}, false, nil
+	}
+
+	c := candidates[i]
+	a := p.explain(c, len(candidates), domain.SourceManual, c.TeamID != settings.TeamID)
+	a.Strategy = domain.StrategyManual
+	a.DueAt = settings.ReviewDueAt(time.Now())
+
+	return a, true, nil
+}
+
+// mustReplaceWithSenior reports whether the replacement for userID has to be a
+// senior to keep the team's senior reviewers quota.
+func (p *PullRequest) mustReplaceWithSenior(ctx context.Context, settings domain.TeamSettings, reviewers []string, userID string) (bool, error) {
+	if settings.SeniorReviewers == 0 {
+		return false, nil
+	}
+
+	seniors, replacingSenior := 0, false
+	for _, id := range reviewers {
+		user, err := p.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if !settings.IsSenior(user.Level) {
+			continue
+		}
+		if id == userID {
+			replacingSenior = true
+		} else {
+			seniors++
+		}
+	}
+
+	return replacingSenior && seniors < settings.SeniorReviewers, nil
+}
+
+// pick runs the configured selector for a PR of the team teamID over the
+// candidates matching filter and returns the size of the pool it chose from.
+func (p *PullRequest) pick(ctx context.Context, teamID int, filter domain.CandidateFilter, n int) ([]domain.Candidate, int, error) {
+	if n <= 0 {
+		return nil, 0, nil
+	}
+
+	candidates, err := p.userRepo.GetReviewCandidates(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return p.selector.Select(teamID, candidates, n), len(candidates), nil
+}
+
+// explain records why c was picked by the configured selector.
+func (p *PullRequest) explain(c domain.Candidate, poolSize int, source string, fromFallback bool) domain.Assignment {
+	return domain.Assignment{
+		ReviewerID: c.ID,
+		Strategy:   p.selector.Name(),
+		PoolSize:   poolSize,
+		Factors: domain.AssignmentFactors{
+			Source:         source,
+			FromFallback:   fromFallback,
+			TeamID:         c.TeamID,
+			Level:          c.Level,
+			OpenReviews:    c.OpenReviews,
+			LastAssignedAt: c.LastAssignedAt,
+		},
+	}
+}
+
+// setDueAt gives assignments made now the due time of the team review SLA.
+func setDueAt(settings domain.TeamSettings, assignments []domain.Assignment) {
+	now := time.Now()
+	for i := range assignments {
+		assignments[i].DueAt = settings.ReviewDueAt(now)
+	}
+}
+
+func matchedRulesResponse(pick reviewerPick) []dto.MatchedRule {
+	if len(pick.matches) == 0 {
+		return nil
+	}
+
+	rules := make([]dto.MatchedRule, len(pick.matches))
+	for i, m := range pick.matches {
+		var reviewers []string
+		for _, r := range pick.reviewers {
+			if m.rule.Owns(r) {
+				reviewers = append(reviewers, r.ID)
+			}
+		}
+		rules[i] = dto.MatchedRule{
+			Pattern:    m.rule.Pattern,
+			Files:      m.files,
+			OwnerUsers: m.rule.OwnerUserIDs,
+			OwnerTeams: m.rule.OwnerTeams,
+			Reviewers:  reviewers,
+		}
+	}
+
+	return rules
+}
+
+func fallbackIDs(assignments []domain.Assignment) []string {
+	var ids []string
+	for _, a := range assignments {
+		if a.Factors.FromFallback {
+			ids = append(ids, a.ReviewerID)
+		}
+	}
+	return ids
+}
+
+func assignmentsResponse(assignments []domain.Assignment) []dto.Assignment {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	resp := make([]dto.Assignment, len(assignments))
+	for i, a := range assignments {
+		resp[i] = dto.Assignment{
+			ReviewerID: a.ReviewerID,
+			AssignedAt: a.AssignedAt,
+			DueAt:      a.DueAt,
+			Strategy:   a.Strategy,
+			PoolSize:   a.PoolSize,
+			Factors:    dto.AssignmentFactors(a.Factors),
+		}
+	}
+	return resp
+}
+
+func countSeniors(settings domain.TeamSettings, candidates []domain.Candidate) int {
+	count := 0
+	for _, c := range candidates {
+		if settings.IsSenior(c.Level) {
+			count++
+		}
+	}
+	return count
+}
+
+func reviewerIDs(candidates []domain.Candidate) []string {
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.ID
+	}
+	return ids
+}
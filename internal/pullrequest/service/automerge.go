@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/rs/zerolog/log"
+)
+
+// SetAutoMerge turns auto-merge on or off for a draft or open PR. Once on, the
+// PR is merged as soon as the merge conditions are met, which may be right
+// away.
+func (p *PullRequest) SetAutoMerge(ctx context.Context, prID, actorID string, enabled bool) (dto.GetPullRequest, error) {
+	const op = "service.pr.SetAutoMerge"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+	if pr.Status != domain.StatusDraft && pr.Status != domain.StatusOpen {
+		return dto.GetPullRequest{}, errutils.Wrap(op, statusError(pr.Status))
+	}
+
+	if err := p.prRepo.SetAutoMerge(ctx, prID, enabled, actor); err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	p.autoMerge(ctx, prID)
+
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+// autoMerge merges an open PR with auto-merge enabled if the merge conditions
+// are met and reports whether it did. It runs after the change that may have
+// met them, so failures are logged instead of failing that change.
+func (p *PullRequest) autoMerge(ctx context.Context, prID string) bool {
+	pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("pr_id", prID).Msg("failed to load pull request for auto-merge")
+		return false
+	}
+	if !pr.AutoMerge || pr.Status != domain.StatusOpen {
+		return false
+	}
+
+	if _, err := p.merge(ctx, prID, domain.MergeOptions{Automatic: true}); err != nil {
+		if !mergeBlocked(err) {
+			log.Logger.Error().Err(err).Str("pr_id", prID).Msg("failed to auto-merge pull request")
+		}
+		return false
+	}
+
+	return true
+}
+
+func (p *PullRequest) autoMergeChildren(ctx context.Context, prID string) {
+	children, err := p.prRepo.GetChildren(ctx, prID)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("pr_id", prID).Msg("failed to load children for auto-merge")
+		return
+	}
+
+	for _, child := range children {
+		p.autoMerge(ctx, child)
+	}
+}
+
+// mergeBlocked reports whether err only says the merge conditions aren't met
+// yet.
+func mergeBlocked(err error) bool {
+	for _, target := range []error{
+		domain.ErrNotApproved,
+		domain.ErrChangesRequested,
+		domain.ErrParentsNotMerged,
+		domain.ErrPullRequestDraft,
+		domain.ErrPullRequestClosed,
+		domain.ErrStatusChanged,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
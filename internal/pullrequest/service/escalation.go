@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	EscalationLead     = "lead"
+	EscalationReassign = "reassign"
+)
+
+var ErrInvalidEscalationPolicy = errors.New("invalid review escalation policy")
+
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
+
+// EscalationPolicy says when a review without a verdict gets a reminder and
+// when it is escalated, both counted from the assignment. Mode picks the
+// escalation: EscalationLead adds the author's team lead as an extra reviewer
+// and falls back to a reassignment when the lead can't take the PR,
+// EscalationReassign always replaces the reviewer.
+type EscalationPolicy struct {
+	RemindAfter   time.Duration
+	EscalateAfter time.Duration
+	Mode          string
+}
+
+func (p EscalationPolicy) validate() error {
+	if p.Mode != EscalationLead && p.Mode != EscalationReassign {
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidEscalationPolicy, p.Mode)
+	}
+	if p.RemindAfter <= 0 || p.EscalateAfter < p.RemindAfter {
+		return fmt.Errorf("%w: escalation must not come before the reminder", ErrInvalidEscalationPolicy)
+	}
+	return nil
+}
+
+// Escalator chases stale reviews of open PRs. Every assignment is reminded and
+// escalated at most once: both steps are claimed with conditional updates, so
+// several instances can run at once. Notifications go out only after the
+// change they announce is committed.
+type Escalator struct {
+	pr       *PullRequest
+	notifier Notifier
+	policy   EscalationPolicy
+}
+
+func NewEscalator(pr *PullRequest, notifier Notifier, policy EscalationPolicy) (*Escalator, error) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	return &Escalator{pr: pr, notifier: notifier, policy: policy}, nil
+}
+
+// ProcessStaleReviews sends the due reminders and then escalates the reviews
+// that stayed without a verdict after their reminder.
+func (e *Escalator) ProcessStaleReviews(ctx context.Context) error {
+	const op = "service.pr.ProcessStaleReviews"
+
+	now := time.Now()
+
+	if err := e.remind(ctx, now.Add(-e.policy.RemindAfter)); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	if err := e.escalate(ctx, now.Add(-e.policy.EscalateAfter)); err != nil {
+		return errutils.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// remind commits the claims before notifying, so a reminder is sent at most
+// once even if the run fails halfway. A failed notification is only logged.
+func (e *Escalator) remind(ctx context.Context, assignedBefore time.Time) error {
+	stale, err := e.pr.prRepo.ClaimReminders(ctx, assignedBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stale {
+		err := e.notifier.Notify(ctx, domain.Notification{
+			Kind:          domain.NotificationReviewReminder,
+			RecipientID:   s.ReviewerID,
+			PullRequestID: s.PullRequestID,
+			Message:       fmt.Sprintf("review of %q is waiting for you since %s", s.PullRequestName, s.AssignedAt.Format(time.RFC3339)),
+		})
+		if err != nil {
+			log.Logger.Error().Err(err).Str("pr_id", s.PullRequestID).Str("reviewer_id", s.ReviewerID).Msg("failed to send review reminder")
+		}
+	}
+
+	return nil
+}
+
+// escalate commits the claims and then escalates every review in its own
+// transaction, so one failure doesn't hold back the others. A failed
+// escalation is logged and its claim released for the next run. The new
+// reviewer is notified and PRs with auto-merge are checked only once the
+// escalation is committed.
+//
+// Escalation is at most once: reviews claimed by a run that dies before
+// escalating them stay marked as escalated and are not picked up again.
+func (e *Escalator) escalate(ctx context.Context, assignedBefore time.Time) error {
+	stale, err := e.pr.prRepo.ClaimEscalations(ctx, assignedBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range stale {
+		var recipientID string
+		err := e.pr.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			recipientID, err = e.escalateReview(ctx, s)
+			return err
+		})
+		if err != nil {
+			log.Logger.Error().Err(err).Str("pr_id", s.PullRequestID).Str("reviewer_id", s.ReviewerID).Msg("failed to escalate stale review")
+			if err := e.pr.prRepo.ReleaseEscalation(ctx, s.PullRequestID, s.ReviewerID); err != nil {
+				log.Logger.Error().Err(err).Str("pr_id", s.PullRequestID).Str("reviewer_id", s.ReviewerID).Msg("failed to release escalation")
+			}
+			continue
+		}
+
+		if recipientID != "" {
+			if err := e.notifyEscalation(ctx, recipientID, s); err != nil {
+				log.Logger.Error().Err(err).Str("pr_id", s.PullRequestID).Str("reviewer_id", recipientID).Msg("failed to send review escalation")
+			}
+		}
+
+		e.pr.autoMerge(ctx, s.PullRequestID)
+	}
+
+	return nil
+}
+
+// escalateReview returns the user the review was escalated to, or an empty ID
+// when nobody could take it.
+func (e *Escalator) escalateReview(ctx context.Context, s domain.StaleReview) (string, error) {
+	pr := domain.PullRequest{ID: s.PullRequestID, Name: s.PullRequestName, AuthorID: s.AuthorID}
+
+	if e.policy.Mode == EscalationLead {
+		leadID, err := e.addLead(ctx, pr, s)
+		if err != nil || leadID != "" {
+			return leadID, err
+		}
+	}
+
+	assignment, err := e.pr.getNewUserIDForPRReview(ctx, pr, s.ReviewerID)
+	if errors.Is(err, domain.ErrNoCandidate) {
+		log.Logger.Warn().Str("pr_id", pr.ID).Str("reviewer_id", s.ReviewerID).Msg("no candidate to escalate stale review to")
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	assignment.Factors.Source = domain.SourceEscalation
+
+	if err := e.pr.prRepo.UpdateReviewer(ctx, pr.ID, s.ReviewerID, assignment, nil); err != nil {
+		return "", err
+	}
+
+	return assignment.ReviewerID, nil
+}
+
+// addLead puts the author's team lead on the PR next to the stale reviewer and
+// returns the lead's ID. The lead must pass the same checks as a replacement:
+// active, present, with spare capacity and not the author, a reviewer or a
+// decliner of the PR. The ID is empty when the team has no lead or the lead
+// isn't eligible.
+func (e *Escalator) addLead(ctx context.Context, pr domain.PullRequest, s domain.StaleReview) (string, error) {
+	settings, filter, err := e.pr.replacementFilter(ctx, pr, s.ReviewerID)
+	if err != nil {
+		return "", err
+	}
+	if settings.LeadID == nil {
+		return "", nil
+	}
+
+	candidates, err := e.pr.userRepo.GetReviewCandidates(ctx, domain.CandidateFilter{
+		UserIDs:    []string{*settings.LeadID},
+		ExcludeIDs: filter.ExcludeIDs,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	lead := candidates[0]
+
+	assignment := domain.Assignment{
+		ReviewerID: lead.ID,
+		DueAt:      settings.ReviewDueAt(time.Now()),
+		Strategy:   domain.StrategyEscalation,
+		PoolSize:   1,
+		Factors: domain.AssignmentFactors{
+			Source: domain.SourceEscalation,
+			TeamID: lead.TeamID,
+			Level:  lead.Level,
+		},
+	}
+	if err := e.pr.prRepo.AddReviewer(ctx, pr.ID, assignment, nil); err != nil {
+		return "", err
+	}
+
+	if err := e.pr.refreshUnderStaffed(ctx, pr.ID, settings); err != nil {
+		return "", err
+	}
+
+	return lead.ID, nil
+}
+
+func (e *Escalator) notifyEscalation(ctx context.Context, recipientID string, s domain.StaleReview) error {
+	return e.notifier.Notify(ctx, domain.Notification{
+		Kind:          domain.NotificationReviewEscalation,
+		RecipientID:   recipientID,
+		PullRequestID: s.PullRequestID,
+		Message:       fmt.Sprintf("review of %q is escalated to you, %s didn't respond", s.PullRequestName, s.ReviewerID),
+	})
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	userrepo "github.com/ilam072/avito-backend-internship/internal/user/repo"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+)
+
+// GetReviewersAsOf returns who was reviewing the PR at the given time.
+func (p *PullRequest) GetReviewersAsOf(ctx context.Context, req dto.ReviewersAsOfRequest) (dto.ReviewersAsOfResponse, error) {
+	const op = "service.pr.GetReviewersAsOf"
+
+	exists, err := p.prRepo.PullRequestExists(ctx, req.PullRequestID)
+	if err != nil {
+		return dto.ReviewersAsOfResponse{}, errutils.Wrap(op, err)
+	}
+	if !exists {
+		return dto.ReviewersAsOfResponse{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+	}
+
+	periods, err := p.prRepo.GetReviewersAt(ctx, req.PullRequestID, req.At)
+	if err != nil {
+		return dto.ReviewersAsOfResponse{}, errutils.Wrap(op, err)
+	}
+
+	reviewers := make([]dto.AssignmentPeriod, len(periods))
+	for i, period := range periods {
+		reviewers[i] = dto.AssignmentPeriod{
+			ReviewerID:    period.ReviewerID,
+			AssignedFrom:  period.ValidFrom,
+			AssignedUntil: period.ValidTo,
+		}
+	}
+
+	return dto.ReviewersAsOfResponse{
+		PullRequestID: req.PullRequestID,
+		At:            req.At,
+		Reviewers:     reviewers,
+	}, nil
+}
+
+// GetReviewQueueAsOf returns the PRs the user was reviewing at the given time.
+// PRs merged by then or closed at that time are left out.
+func (p *PullRequest) GetReviewQueueAsOf(ctx context.Context, req dto.ReviewQueueAsOfRequest) (dto.ReviewQueueAsOfResponse, error) {
+	const op = "service.pr.GetReviewQueueAsOf"
+
+	if _, err := p.userRepo.GetUserByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return dto.ReviewQueueAsOfResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.ReviewQueueAsOfResponse{}, errutils.Wrap(op, err)
+	}
+
+	periods, err := p.prRepo.GetReviewQueueAt(ctx, req.UserID, req.At)
+	if err != nil {
+		return dto.ReviewQueueAsOfResponse{}, errutils.Wrap(op, err)
+	}
+
+	queue := make([]dto.QueuedReview, len(periods))
+	for i, period := range periods {
+		queue[i] = dto.QueuedReview{
+			PullRequestID:   period.PullRequest.ID,
+			PullRequestName: period.PullRequest.Name,
+			AuthorID:        period.PullRequest.AuthorID,
+			AssignedFrom:    period.ValidFrom,
+			AssignedUntil:   period.ValidTo,
+		}
+	}
+
+	return dto.ReviewQueueAsOfResponse{
+		UserID:       req.UserID,
+		At:           req.At,
+		PullRequests: queue,
+	}, nil
+}
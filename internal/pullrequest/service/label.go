@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"slices"
+)
+
+// labeledSettings returns the team settings with the policies bound to any of
+// labels applied, together with those policies.
+func (p *PullRequest) labeledSettings(ctx context.Context, teamID int, labels []string) (domain.TeamSettings, []domain.LabelPolicy, error) {
+	settings, err := p.teamRepo.GetTeamSettings(ctx, teamID)
+	if err != nil {
+		return domain.TeamSettings{}, nil, err
+	}
+
+	policies, err := p.labelPolicies(ctx, teamID, labels)
+	if err != nil {
+		return domain.TeamSettings{}, nil, err
+	}
+
+	return settings.WithLabelPolicies(policies), policies, nil
+}
+
+// labelPolicies returns the policies of the team bound to any of labels.
+func (p *PullRequest) labelPolicies(ctx context.Context, teamID int, labels []string) ([]domain.LabelPolicy, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	all, err := p.teamRepo.GetLabelPolicies(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []domain.LabelPolicy
+	for _, policy := range all {
+		if slices.Contains(labels, policy.Label) {
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies, nil
+}
+
+// unmetPolicies returns the policies requiring a group none of reviewers
+// belongs to.
+func unmetPolicies(policies []domain.LabelPolicy, reviewers []domain.Candidate) []domain.LabelPolicy {
+	var unmet []domain.LabelPolicy
+	for _, policy := range policies {
+		if policy.RequiresGroup() && !slices.ContainsFunc(reviewers, policy.InGroup) {
+			unmet = append(unmet, policy)
+		}
+	}
+	return unmet
+}
+
+// AddLabels attaches labels to a PR that isn't merged yet. Reviewers already
+// assigned stay as they are, new overrides only change the quorum and the
+// staffing check of the PR.
+func (p *PullRequest) AddLabels(ctx context.Context, prID, actorID string, labels []string) (dto.GetPullRequest, error) {
+	const op = "service.pr.AddLabels"
+
+	pr, err := p.changeLabels(ctx, prID, actorID, labels, p.prRepo.AddLabels)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+func (p *PullRequest) RemoveLabels(ctx context.Context, prID, actorID string, labels []string) (dto.GetPullRequest, error) {
+	const op = "service.pr.RemoveLabels"
+
+	pr, err := p.changeLabels(ctx, prID, actorID, labels, p.prRepo.RemoveLabels)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+func (p *PullRequest) changeLabels(
+	ctx context.Context,
+	prID, actorID string,
+	labels []string,
+	change func(ctx context.Context, prID string, labels []string, actorID *string) error,
+) (domain.PullRequest, error) {
+	labels, err := domain.NormalizeLabels(labels)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+				return domain.ErrPullRequestNotFound
+			}
+			return err
+		}
+		if pr.Status == domain.StatusMerged {
+			return domain.ErrPullRequestMerged
+		}
+
+		if err := change(ctx, prID, labels, actor); err != nil {
+			return err
+		}
+
+		if pr.Status != domain.StatusOpen {
+			return nil
+		}
+
+		settings, err := p.authorTeamSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+		return p.refreshUnderStaffed(ctx, prID, settings)
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	p.autoMerge(ctx, prID)
+
+	return p.getPullRequestWithReviewers(ctx, prID)
+}
+
+func labelPoliciesResponse(policies []domain.LabelPolicy) []dto.LabelPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	resp := make([]dto.LabelPolicy, len(policies))
+	for i, policy := range policies {
+		resp[i] = dto.LabelPolicy{
+			Label:             policy.Label,
+			ReviewerCount:     policy.ReviewerCount,
+			MinReviewers:      policy.MinReviewers,
+			RequiredApprovals: policy.RequiredApprovals,
+			Users:             policy.GroupUserIDs,
+			Teams:             policy.GroupTeams,
+		}
+	}
+	return resp
+}
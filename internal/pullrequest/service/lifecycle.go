@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"slices"
+)
+
+// transitions lists the statuses a PR may move to from each status. MERGED is
+// final.
+var transitions = map[string][]string{
+	domain.StatusDraft:  {domain.StatusOpen, domain.StatusClosed},
+	domain.StatusOpen:   {domain.StatusMerged, domain.StatusClosed},
+	domain.StatusClosed: {domain.StatusOpen},
+}
+
+// checkTransition rejects a move the state machine doesn't allow with the error
+// of the current status.
+func checkTransition(from, to string) error {
+	if slices.Contains(transitions[from], to) {
+		return nil
+	}
+	return statusError(from)
+}
+
+func statusError(status string) error {
+	switch status {
+	case domain.StatusDraft:
+		return domain.ErrPullRequestDraft
+	case domain.StatusOpen:
+		return domain.ErrPullRequestOpen
+	case domain.StatusMerged:
+		return domain.ErrPullRequestMerged
+	case domain.StatusClosed:
+		return domain.ErrPullRequestClosed
+	default:
+		return fmt.Errorf("unknown pull request status %q", status)
+	}
+}
+
+// MarkReady opens a draft and assigns its reviewers the same way a new PR
+// gets them.
+func (p *PullRequest) MarkReady(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.MarkReady"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	var pick reviewerPick
+	pr, err := p.transition(ctx, prID, domain.StatusOpen, actor, func(ctx context.Context, pr domain.PullRequest) error {
+		if pr.Status != domain.StatusDraft {
+			return statusError(pr.Status)
+		}
+
+		var err error
+		pick, err = p.staffPullRequest(ctx, pr, actor)
+		return err
+	})
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	prResp := pullRequestResponse(pr)
+	prResp.Fallback = fallbackIDs(pick.assignments)
+	prResp.MatchedRules = matchedRulesResponse(pick)
+	prResp.AppliedPolicies = labelPoliciesResponse(pick.policies)
+	prResp.UnmetPolicies = labelPoliciesResponse(pick.unmet)
+
+	return prResp, nil
+}
+
+// ClosePullRequest abandons a draft or open PR. Its reviewers stay assigned
+// but no longer count towards their open reviews.
+func (p *PullRequest) ClosePullRequest(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.Close"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	pr, err := p.transition(ctx, prID, domain.StatusClosed, actor, nil)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+// ReopenPullRequest opens a closed PR again. A PR closed as a draft has no
+// reviewers yet and is staffed like on MarkReady.
+func (p *PullRequest) ReopenPullRequest(ctx context.Context, prID, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.Reopen"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	var pick reviewerPick
+	pr, err := p.transition(ctx, prID, domain.StatusOpen, actor, func(ctx context.Context, pr domain.PullRequest) error {
+		if pr.Status != domain.StatusClosed {
+			return statusError(pr.Status)
+		}
+
+		reviewers, err := p.prRepo.GetPullRequestReviewers(ctx, pr.ID)
+		if err != nil {
+			return err
+		}
+		if len(reviewers) == 0 {
+			pick, err = p.staffPullRequest(ctx, pr, actor)
+			return err
+		}
+
+		settings, err := p.authorTeamSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+		return p.refreshUnderStaffed(ctx, pr.ID, settings)
+	})
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	prResp := pullRequestResponse(pr)
+	prResp.UnmetPolicies = labelPoliciesResponse(pick.unmet)
+
+	return prResp, nil
+}
+
+// transition moves the PR to the given status on behalf of actor in a
+// transaction. The optional prepare hook runs after the transition is checked
+// and before the status is stored.
+func (p *PullRequest) transition(
+	ctx context.Context,
+	prID, to string,
+	actor *string,
+	prepare func(ctx context.Context, pr domain.PullRequest) error,
+) (domain.PullRequest, error) {
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+				return domain.ErrPullRequestNotFound
+			}
+			return err
+		}
+
+		if err := checkTransition(pr.Status, to); err != nil {
+			return err
+		}
+
+		if prepare != nil {
+			if err := prepare(ctx, pr); err != nil {
+				return err
+			}
+		}
+
+		if err := p.prRepo.UpdateStatus(ctx, prID, pr.Status, to, actor); err != nil {
+			if errors.Is(err, prrepo.ErrStatusChanged) {
+				return domain.ErrStatusChanged
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if to == domain.StatusOpen {
+		p.autoMerge(ctx, prID)
+	}
+
+	return p.getPullRequestWithReviewers(ctx, prID)
+}
+
+// staffPullRequest assigns reviewers to a PR that has none on behalf of actor.
+func (p *PullRequest) staffPullRequest(ctx context.Context, pr domain.PullRequest, actor *string) (reviewerPick, error) {
+	author, err := p.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return reviewerPick{}, err
+	}
+
+	settings, policies, err := p.labeledSettings(ctx, author.TeamID, pr.Labels)
+	if err != nil {
+		return reviewerPick{}, err
+	}
+
+	pick, err := p.staffReviewers(ctx, author, settings, pr.Files, policies)
+	if err != nil {
+		return reviewerPick{}, err
+	}
+
+	for _, a := range pick.assignments {
+		if err := p.prRepo.AddReviewer(ctx, pr.ID, a, actor); err != nil {
+			return reviewerPick{}, err
+		}
+	}
+
+	if err := p.prRepo.SetUnderStaffed(ctx, pr.ID, pick.underStaffed(settings)); err != nil {
+		return reviewerPick{}, err
+	}
+
+	return pick, nil
+}
+
+// staffReviewers picks reviewers for a PR and enforces the team minimums. A
+// label policy whose group has no available reviewer doesn't fail the PR, it
+// is reported in the unmet policies of the pick and leaves the PR
+// under-staffed.
+func (p *PullRequest) staffReviewers(ctx context.Context, author domain.User, settings domain.TeamSettings, files []string, policies []domain.LabelPolicy) (reviewerPick, error) {
+	pick, err := p.pickReviewers(ctx, author, settings, files, policies)
+	if err != nil {
+		return reviewerPick{}, err
+	}
+	pick.unmet = unmetPolicies(policies, pick.reviewers)
+	if len(pick.reviewers) < settings.MinReviewers {
+		return reviewerPick{}, domain.ErrNotEnoughReviewers
+	}
+	if countSeniors(settings, pick.reviewers) < settings.SeniorReviewers {
+		return reviewerPick{}, domain.ErrNotEnoughSeniors
+	}
+
+	return pick, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	allowed := []struct{ from, to string }{
+		{domain.StatusDraft, domain.StatusOpen},
+		{domain.StatusDraft, domain.StatusClosed},
+		{domain.StatusOpen, domain.StatusMerged},
+		{domain.StatusOpen, domain.StatusClosed},
+		{domain.StatusClosed, domain.StatusOpen},
+	}
+	for _, tr := range allowed {
+		assert.NoError(t, checkTransition(tr.from, tr.to), "%s -> %s", tr.from, tr.to)
+	}
+
+	rejected := []struct {
+		from, to string
+		err      error
+	}{
+		{domain.StatusDraft, domain.StatusMerged, domain.ErrPullRequestDraft},
+		{domain.StatusClosed, domain.StatusMerged, domain.ErrPullRequestClosed},
+		{domain.StatusClosed, domain.StatusClosed, domain.ErrPullRequestClosed},
+		{domain.StatusMerged, domain.StatusOpen, domain.ErrPullRequestMerged},
+		{domain.StatusMerged, domain.StatusClosed, domain.ErrPullRequestMerged},
+		{domain.StatusOpen, domain.StatusDraft, domain.ErrPullRequestOpen},
+	}
+	for _, tr := range rejected {
+		assert.ErrorIs(t, checkTransition(tr.from, tr.to), tr.err, "%s -> %s", tr.from, tr.to)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+)
+
+// approvalStatus summarizes verdicts of the currently assigned reviewers
+// against the team quota.
+type approvalStatus struct {
+	approvals        int
+	required         int
+	changesRequested bool
+}
+
+func newApprovalStatus(reviews []domain.Review, required int) approvalStatus {
+	status := approvalStatus{required: required}
+	for _, review := range reviews {
+		switch review.Verdict {
+		case domain.VerdictApproved:
+			status.approvals++
+		case domain.VerdictChangesRequested:
+			status.changesRequested = true
+		}
+	}
+	return status
+}
+
+// check reports why the PR can't be merged yet. Change requests take
+// precedence over a missing quorum.
+func (s approvalStatus) check() error {
+	if s.changesRequested {
+		return domain.ErrChangesRequested
+	}
+	if s.approvals < s.required {
+		return domain.ErrNotApproved
+	}
+	return nil
+}
+
+func (s approvalStatus) response() dto.ApprovalStatus {
+	return dto.ApprovalStatus{
+		Approvals:         s.approvals,
+		RequiredApprovals: s.required,
+		ChangesRequested:  s.changesRequested,
+		Mergeable:         s.check() == nil,
+	}
+}
+
+func (p *PullRequest) approvalStatus(ctx context.Context, pr domain.PullRequest) (approvalStatus, error) {
+	settings, err := p.authorTeamSettings(ctx, pr)
+	if err != nil {
+		return approvalStatus{}, err
+	}
+
+	reviews, err := p.prRepo.GetReviews(ctx, pr.ID)
+	if err != nil {
+		return approvalStatus{}, err
+	}
+
+	return newApprovalStatus(reviews, settings.RequiredApprovals), nil
+}
+
+// SubmitReview stores the verdict of an assigned reviewer and returns the
+// resulting approval status of the PR. An approval that meets the merge
+// conditions of an auto-merge PR merges it.
+func (p *PullRequest) SubmitReview(ctx context.Context, prID, reviewerID, verdict, comment string) (dto.SubmitReviewResponse, error) {
+	const op = "service.pr.SubmitReview"
+
+	pr, err := p.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return dto.SubmitReviewResponse{}, errutils.Wrap(op, err)
+	}
+
+	assigned, err := p.prRepo.IsUserAssignedForPR(ctx, prID, reviewerID)
+	if err != nil {
+		return dto.SubmitReviewResponse{}, errutils.Wrap(op, err)
+	}
+	if !assigned {
+		return dto.SubmitReviewResponse{}, errutils.Wrap(op, domain.ErrUserNotAssignedForPR)
+	}
+
+	review := domain.Review{
+		PullRequestID: prID,
+		ReviewerID:    reviewerID,
+		Verdict:       verdict,
+	}
+	if comment != "" {
+		review.Comment = &comment
+	}
+
+	review, err = p.prRepo.SubmitReview(ctx, review)
+	if err != nil {
+		return dto.SubmitReviewResponse{}, errutils.Wrap(op, err)
+	}
+
+	status, err := p.approvalStatus(ctx, pr)
+	if err != nil {
+		return dto.SubmitReviewResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.SubmitReviewResponse{
+		PullRequestID: prID,
+		Review: dto.Review{
+			ReviewerID:  review.ReviewerID,
+			Verdict:     review.Verdict,
+			Comment:     review.Comment,
+			SubmittedAt: review.SubmittedAt,
+		},
+		Status:     status.response(),
+		AutoMerged: p.autoMerge(ctx, prID),
+	}, nil
+}
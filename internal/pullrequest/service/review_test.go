@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApprovalStatus(t *testing.T) {
+	review := func(verdict string) domain.Review {
+		return domain.Review{Verdict: verdict}
+	}
+
+	t.Run("QuorumMet", func(t *testing.T) {
+		s := newApprovalStatus([]domain.Review{
+			review(domain.VerdictApproved),
+			review(domain.VerdictCommented),
+			review(domain.VerdictApproved),
+		}, 2)
+		assert.NoError(t, s.check())
+		assert.True(t, s.response().Mergeable)
+		assert.Equal(t, 2, s.response().Approvals)
+	})
+
+	t.Run("NotEnoughApprovals", func(t *testing.T) {
+		s := newApprovalStatus([]domain.Review{review(domain.VerdictApproved)}, 2)
+		assert.ErrorIs(t, s.check(), domain.ErrNotApproved)
+		assert.False(t, s.response().Mergeable)
+	})
+
+	t.Run("ChangesRequestedWins", func(t *testing.T) {
+		s := newApprovalStatus([]domain.Review{
+			review(domain.VerdictApproved),
+			review(domain.VerdictChangesRequested),
+		}, 1)
+		assert.ErrorIs(t, s.check(), domain.ErrChangesRequested)
+	})
+
+	t.Run("NoQuorumRequired", func(t *testing.T) {
+		assert.NoError(t, newApprovalStatus(nil, 0).check())
+	})
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+var ErrUnknownStrategy = errors.New("unknown reviewer selection strategy")
+
+// ReviewerSelector picks up to n reviewers from the eligible candidates for a
+// PR of the team teamID. Rank orders all candidates the way Select would pick
+// them without changing any selector state; a selector without a preference
+// orders them by ID. Name is recorded with every assignment the selector
+// makes.
+type ReviewerSelector interface {
+	Name() string
+	Select(teamID int, candidates []domain.Candidate, n int) []domain.Candidate
+	Rank(teamID int, candidates []domain.Candidate) []domain.Candidate
+}
+
+func NewReviewerSelector(strategy string) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return NewRandomSelector(), nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyLeastLoaded:
+		return NewLeastLoadedSelector(), nil
+	default:
+		return nil, ErrUnknownStrategy
+	}
+}
+
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) Name() string {
+	return StrategyRandom
+}
+
+func (s *RandomSelector) Select(_ int, candidates []domain.Candidate, n int) []domain.Candidate {
+	shuffled := slices.Clone(candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return head(shuffled, n)
+}
+
+// Rank orders the candidates by ID: Select gives each of them the same chance.
+func (s *RandomSelector) Rank(_ int, candidates []domain.Candidate) []domain.Candidate {
+	sorted := slices.Clone(candidates)
+	slices.SortFunc(sorted, func(a, b domain.Candidate) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return sorted
+}
+
+// RoundRobinSelector remembers the last picked reviewer per staffed team and
+// continues from the next one by ID, whichever teams the candidates come from.
+// The cursor lives in memory of a single instance.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[int]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[int]string)}
+}
+
+func (s *RoundRobinSelector) Name() string {
+	return StrategyRoundRobin
+}
+
+func (s *RoundRobinSelector) Select(teamID int, candidates []domain.Candidate, n int) []domain.Candidate {
+	if n <= 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ranked := s.rotate(teamID, candidates)
+	picked := head(ranked, n)
+	s.last[teamID] = picked[len(picked)-1].ID
+
+	return picked
+}
+
+func (s *RoundRobinSelector) Rank(teamID int, candidates []domain.Candidate) []domain.Candidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rotate(teamID, candidates)
+}
+
+// rotate orders candidates by ID starting right after the cursor of teamID.
+// Callers must hold s.mu.
+func (s *RoundRobinSelector) rotate(teamID int, candidates []domain.Candidate) []domain.Candidate {
+	sorted := slices.Clone(candidates)
+	slices.SortFunc(sorted, func(a, b domain.Candidate) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	start := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].ID > s.last[teamID]
+	})
+
+	return append(sorted[start:], sorted[:start]...)
+}
+
+// LeastLoadedSelector prefers candidates with the fewest open reviews. Ties are
+// broken by the oldest last assignment (never assigned goes first), then by ID.
+type LeastLoadedSelector struct{}
+
+func NewLeastLoadedSelector() *LeastLoadedSelector {
+	return &LeastLoadedSelector{}
+}
+
+func (s *LeastLoadedSelector) Name() string {
+	return StrategyLeastLoaded
+}
+
+func (s *LeastLoadedSelector) Select(teamID int, candidates []domain.Candidate, n int) []domain.Candidate {
+	return head(s.Rank(teamID, candidates), n)
+}
+
+func (s *LeastLoadedSelector) Rank(_ int, candidates []domain.Candidate) []domain.Candidate {
+	sorted := slices.Clone(candidates)
+	slices.SortFunc(sorted, compareLoad)
+
+	return sorted
+}
+
+func compareLoad(a, b domain.Candidate) int {
+	if a.OpenReviews != b.OpenReviews {
+		return a.OpenReviews - b.OpenReviews
+	}
+
+	switch {
+	case a.LastAssignedAt == nil && b.LastAssignedAt != nil:
+		return -1
+	case a.LastAssignedAt != nil && b.LastAssignedAt == nil:
+		return 1
+	case a.LastAssignedAt != nil && b.LastAssignedAt != nil:
+		if c := a.LastAssignedAt.Compare(*b.LastAssignedAt); c != 0 {
+			return c
+		}
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+func head(candidates []domain.Candidate, n int) []domain.Candidate {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	return candidates[:n]
+}
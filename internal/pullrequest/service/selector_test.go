@@ -0,0 +1,101 @@
+package service
+
+import (
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewReviewerSelector(t *testing.T) {
+	for _, strategy := range []string{StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded} {
+		s, err := NewReviewerSelector(strategy)
+		require.NoError(t, err, strategy)
+		assert.Equal(t, strategy, s.Name())
+	}
+
+	_, err := NewReviewerSelector("unknown")
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+}
+
+func TestRandomSelector(t *testing.T) {
+	candidates := []domain.Candidate{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	s := NewRandomSelector()
+
+	t.Run("PicksDistinctCandidates", func(t *testing.T) {
+		picked := s.Select(1, candidates, 2)
+		require.Len(t, picked, 2)
+		assert.NotEqual(t, picked[0].ID, picked[1].ID)
+		assert.Subset(t, reviewerIDs(candidates), reviewerIDs(picked))
+	})
+
+	t.Run("NotEnoughCandidates", func(t *testing.T) {
+		assert.Len(t, s.Select(1, candidates, 5), 3)
+		assert.Empty(t, s.Select(1, nil, 2))
+	})
+
+	t.Run("RankReturnsAllByID", func(t *testing.T) {
+		shuffled := []domain.Candidate{{ID: "c"}, {ID: "a"}, {ID: "b"}}
+		assert.Equal(t, reviewerIDs(candidates), reviewerIDs(s.Rank(1, shuffled)))
+	})
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	candidates := []domain.Candidate{{ID: "c", TeamID: 1}, {ID: "a", TeamID: 1}, {ID: "b", TeamID: 1}}
+	s := NewRoundRobinSelector()
+
+	assert.Equal(t, []string{"a", "b"}, reviewerIDs(s.Select(1, candidates, 2)))
+	assert.Equal(t, []string{"c", "a"}, reviewerIDs(s.Select(1, candidates, 2)))
+	assert.Equal(t, []string{"b"}, reviewerIDs(s.Select(1, candidates, 1)))
+
+	t.Run("RankKeepsCursor", func(t *testing.T) {
+		assert.Equal(t, []string{"c", "a", "b"}, reviewerIDs(s.Rank(1, candidates)))
+		assert.Equal(t, []string{"c", "a", "b"}, reviewerIDs(s.Rank(1, candidates)))
+	})
+
+	t.Run("SeparateCursorPerTeam", func(t *testing.T) {
+		other := []domain.Candidate{{ID: "x", TeamID: 2}, {ID: "y", TeamID: 2}}
+		assert.Equal(t, []string{"x"}, reviewerIDs(s.Select(2, other, 1)))
+		assert.Equal(t, []string{"c"}, reviewerIDs(s.Select(1, candidates, 1)))
+	})
+
+	t.Run("CursorKeyedByStaffedTeam", func(t *testing.T) {
+		fallback := []domain.Candidate{{ID: "a", TeamID: 3}, {ID: "b", TeamID: 3}, {ID: "c", TeamID: 3}}
+		s := NewRoundRobinSelector()
+
+		assert.Equal(t, []string{"a"}, reviewerIDs(s.Select(1, fallback, 1)))
+		assert.Equal(t, []string{"b"}, reviewerIDs(s.Select(1, candidates, 1)))
+		assert.Equal(t, []string{"a"}, reviewerIDs(s.Select(3, candidates, 1)))
+	})
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	candidates := []domain.Candidate{
+		{ID: "busy", OpenReviews: 4},
+		{ID: "free", OpenReviews: 0},
+		{ID: "some", OpenReviews: 1},
+	}
+	s := NewLeastLoadedSelector()
+
+	assert.Equal(t, []string{"free", "some"}, reviewerIDs(s.Select(1, candidates, 2)))
+	assert.Equal(t, []string{"free", "some", "busy"}, reviewerIDs(s.Select(1, candidates, 3)))
+	assert.Equal(t, []string{"free", "some", "busy"}, reviewerIDs(s.Rank(1, candidates)))
+}
+
+func TestLeastLoadedSelector_TieBreaking(t *testing.T) {
+	earlier := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	candidates := []domain.Candidate{
+		{ID: "recent", OpenReviews: 1, LastAssignedAt: &later},
+		{ID: "old", OpenReviews: 1, LastAssignedAt: &earlier},
+		{ID: "b-never", OpenReviews: 1},
+		{ID: "a-never", OpenReviews: 1},
+	}
+	s := NewLeastLoadedSelector()
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, []string{"a-never", "b-never", "old", "recent"}, reviewerIDs(s.Select(1, candidates, 4)))
+	}
+}
@@ -4,36 +4,83 @@ import (
 	"context"
 	"errors"
 	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	teamrepo "github.com/ilam072/avito-backend-internship/internal/team/repo"
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
 	"github.com/ilam072/avito-backend-internship/internal/types/dto"
 	userrepo "github.com/ilam072/avito-backend-internship/internal/user/repo"
 	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/ilam072/avito-backend-internship/pkg/pagination"
+	"slices"
+	"time"
 )
 
 type PullRequestRepo interface {
 	CreatePullRequest(ctx context.Context, pr domain.PullRequest) (domain.PullRequest, error)
 	GetPullRequestByID(ctx context.Context, ID string) (domain.PullRequest, error)
+	LockPullRequest(ctx context.Context, ID string) error
 	GetPullRequestReviewers(ctx context.Context, ID string) ([]string, error)
-	GetPRsWhereUserIsReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
-	UpdateReviewer(ctx context.Context, prID, oldUserID, newUserID string) error
-	MergePullRequest(ctx context.Context, ID string) (domain.PullRequest, error)
+	GetPRsWhereUserIsReviewer(ctx context.Context, userID string, filter domain.ReviewFilter) ([]domain.ReviewAssignment, error)
+	GetUnderStaffedPullRequests(ctx context.Context, teamName string) ([]domain.PullRequest, error)
+	GetPullRequestAssignments(ctx context.Context, ID string) ([]domain.Assignment, error)
+	UpdateReviewer(ctx context.Context, prID, oldUserID string, a domain.Assignment, actorID *string) error
+	AddReviewer(ctx context.Context, prID string, a domain.Assignment, actorID *string) error
+	RemoveReviewer(ctx context.Context, prID, userID string, actorID *string) error
+	SetUnderStaffed(ctx context.Context, prID string, underStaffed bool) error
+	DeclineReview(ctx context.Context, prID, userID, reason string) error
+	GetDecliners(ctx context.Context, prID string) ([]string, error)
+	SubmitReview(ctx context.Context, review domain.Review) (domain.Review, error)
+	GetReviews(ctx context.Context, prID string) ([]domain.Review, error)
+	UpdateStatus(ctx context.Context, ID, from, to string, actorID *string) error
+	UpdateName(ctx context.Context, ID, name string, actorID *string) error
+	ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) ([]domain.PullRequest, error)
+	GetOverdueReviews(ctx context.Context, teamName, userID string) ([]domain.OverdueReview, error)
+	ClaimReminders(ctx context.Context, assignedBefore time.Time) ([]domain.StaleReview, error)
+	ClaimEscalations(ctx context.Context, assignedBefore time.Time) ([]domain.StaleReview, error)
+	ReleaseEscalation(ctx context.Context, prID, reviewerID string) error
+	GetLabels(ctx context.Context, prID string) ([]string, error)
+	AddLabels(ctx context.Context, prID string, labels []string, actorID *string) error
+	RemoveLabels(ctx context.Context, prID string, labels []string, actorID *string) error
+	AddParents(ctx context.Context, prID string, parentIDs []string, actorID *string) error
+	RemoveParents(ctx context.Context, prID string, parentIDs []string, actorID *string) error
+	GetUnmergedParents(ctx context.Context, prID string) ([]string, error)
+	GetChildren(ctx context.Context, prID string) ([]string, error)
+	GetEvents(ctx context.Context, prID string) ([]domain.Event, error)
+	GetReviewersAt(ctx context.Context, prID string, at time.Time) ([]domain.AssignmentPeriod, error)
+	GetReviewQueueAt(ctx context.Context, userID string, at time.Time) ([]domain.AssignmentPeriod, error)
+	GetStack(ctx context.Context, prID string) ([]domain.PullRequest, error)
+	MergePullRequest(ctx context.Context, ID string, opts domain.MergeOptions) (domain.PullRequest, error)
+	SetAutoMerge(ctx context.Context, ID string, enabled bool, actorID *string) error
 	IsUserAssignedForPR(ctx context.Context, prID, userID string) (bool, error)
 	PullRequestExists(ctx context.Context, id string) (bool, error)
 }
 
 type UserRepo interface {
 	GetUserByID(ctx context.Context, ID string) (domain.User, error)
-	GetNewUserIDForPRReview(ctx context.Context, prID string, oldUserID string) (string, error)
-	UserExists(ctx context.Context, id string) (bool, error)
+	GetReviewCandidates(ctx context.Context, filter domain.CandidateFilter) ([]domain.Candidate, error)
+}
+
+type TeamRepo interface {
+	GetTeamSettings(ctx context.Context, teamID int) (domain.TeamSettings, error)
+	GetTeamSettingsByName(ctx context.Context, name string) (domain.TeamSettings, error)
+	GetOwnershipRules(ctx context.Context, teamID int) ([]domain.OwnershipRule, error)
+	GetLabelPolicies(ctx context.Context, teamID int) ([]domain.LabelPolicy, error)
+	GetTeamNameByID(ctx context.Context, ID int) (string, error)
+}
+
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type PullRequest struct {
 	userRepo UserRepo
 	prRepo   PullRequestRepo
+	teamRepo TeamRepo
+	selector ReviewerSelector
+	tx       Transactor
 }
 
-func NewPullRequest(userRepo UserRepo, prRepo PullRequestRepo) *PullRequest {
-	return &PullRequest{userRepo: userRepo, prRepo: prRepo}
+func NewPullRequest(userRepo UserRepo, prRepo PullRequestRepo, teamRepo TeamRepo, selector ReviewerSelector, tx Transactor) *PullRequest {
+	return &PullRequest{userRepo: userRepo, prRepo: prRepo, teamRepo: teamRepo, selector: selector, tx: tx}
 }
 
 func (p *PullRequest) CreatePullRequest(ctx context.Context, pr dto.CreatePullRequest) (dto.GetPullRequest, error) {
@@ -47,51 +94,174 @@ func (p *PullRequest) CreatePullRequest(ctx context.Context, pr dto.CreatePullRe
 		return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestExists)
 	}
 
-	exists, err = p.userRepo.UserExists(ctx, pr.AuthorID)
+	author, err := p.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	labels, err := domain.NormalizeLabels(pr.Labels)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	settings, policies, err := p.labeledSettings(ctx, author.TeamID, labels)
 	if err != nil {
 		return dto.GetPullRequest{}, errutils.Wrap(op, err)
 	}
-	if !exists {
-		return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrUserNotFound)
+
+	// Drafts get their reviewers once marked ready.
+	status := domain.StatusDraft
+	var pick reviewerPick
+	if !pr.Draft {
+		status = domain.StatusOpen
+		pick, err = p.staffReviewers(ctx, author, settings, pr.Files, policies)
+		if err != nil {
+			return dto.GetPullRequest{}, errutils.Wrap(op, err)
+		}
 	}
 
-	prDomain, err := p.prRepo.CreatePullRequest(ctx, domain.PullRequest{
-		ID:       pr.ID,
-		Name:     pr.Name,
-		AuthorID: pr.AuthorID,
+	var prDomain domain.PullRequest
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		prDomain, err = p.prRepo.CreatePullRequest(ctx, domain.PullRequest{
+			ID:           pr.ID,
+			Name:         pr.Name,
+			AuthorID:     pr.AuthorID,
+			Status:       status,
+			Files:        pr.Files,
+			Labels:       labels,
+			Reviewers:    reviewerIDs(pick.reviewers),
+			Assignments:  pick.assignments,
+			UnderStaffed: !pr.Draft && pick.underStaffed(settings),
+		})
+		if err != nil || len(pr.ParentIDs) == 0 {
+			return err
+		}
+
+		prDomain.ParentIDs = uniqueIDs(pr.ParentIDs)
+		return p.addParents(ctx, prDomain.ID, prDomain.ParentIDs, &prDomain.AuthorID)
 	})
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
 
-	return dto.GetPullRequest{
-		ID:        prDomain.ID,
-		Name:      prDomain.Name,
-		AuthorID:  prDomain.AuthorID,
-		Status:    prDomain.Status,
-		Reviewers: prDomain.Reviewers,
-	}, nil
+	prDomain.Assignments, err = p.prRepo.GetPullRequestAssignments(ctx, prDomain.ID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	prResp := pullRequestResponse(prDomain)
+	prResp.Fallback = fallbackIDs(pick.assignments)
+	prResp.MatchedRules = matchedRulesResponse(pick)
+	prResp.AppliedPolicies = labelPoliciesResponse(pick.policies)
+	prResp.UnmetPolicies = labelPoliciesResponse(pick.unmet)
+
+	return prResp, nil
 }
 
-func (p *PullRequest) MergePullRequest(ctx context.Context, ID string) (dto.PRResponse, error) {
+// MergePullRequest merges the PR once its parents are merged, the author's
+// team approval quorum is met and no assigned reviewer requests changes. The
+// override skips the approval check, not the parents one, and is recorded
+// together with the user who merged.
+func (p *PullRequest) MergePullRequest(ctx context.Context, ID string, override bool, actorID string) (dto.PRResponse, error) {
 	const op = "service.pr.Merge"
 
-	pr, err := p.prRepo.MergePullRequest(ctx, ID)
+	mergedBy, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.PRResponse{}, errutils.Wrap(op, err)
+	}
+
+	pr, err := p.merge(ctx, ID, domain.MergeOptions{Override: override, MergedBy: mergedBy})
+	if err != nil {
+		return dto.PRResponse{}, errutils.Wrap(op, err)
+	}
+
+	assignments, err := p.prRepo.GetPullRequestAssignments(ctx, ID)
 	if err != nil {
-		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
-			return dto.PRResponse{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
-		}
 		return dto.PRResponse{}, errutils.Wrap(op, err)
 	}
 
 	return dto.PRResponse{
-		ID:        pr.ID,
-		Name:      pr.Name,
-		AuthorID:  pr.AuthorID,
-		Status:    pr.Status,
-		Reviewers: pr.Reviewers,
-		MergedAt:  *pr.MergedAt,
+		ID:            pr.ID,
+		Name:          pr.Name,
+		AuthorID:      pr.AuthorID,
+		Status:        pr.Status,
+		Reviewers:     pr.Reviewers,
+		Assignments:   assignmentsResponse(assignments),
+		MergeOverride: pr.MergeOverride,
+		MergedBy:      pr.MergedBy,
+		AutoMerged:    pr.AutoMerged,
+		MergedAt:      *pr.MergedAt,
 	}, nil
 }
 
-func (p *PullRequest) ReassignReviewer(ctx context.Context, prID string, userID string) (dto.ReassignResponse, error) {
+// merge checks the merge conditions and merges the PR in a transaction. The
+// PR is locked before the check, so verdicts, reviewer and parent changes
+// can't slip in between the check and the merge. A PR that is already merged
+// is returned as is. Children that wait for the PR with auto-merge enabled are
+// merged next if they are ready.
+func (p *PullRequest) merge(ctx context.Context, ID string, opts domain.MergeOptions) (domain.PullRequest, error) {
+	var (
+		pr     domain.PullRequest
+		merged bool
+	)
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.prRepo.LockPullRequest(ctx, ID); err != nil {
+			if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+				return domain.ErrPullRequestNotFound
+			}
+			return err
+		}
+
+		current, err := p.prRepo.GetPullRequestByID(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != domain.StatusMerged {
+			if err := checkTransition(current.Status, domain.StatusMerged); err != nil {
+				return err
+			}
+			if err := p.checkParentsMerged(ctx, ID); err != nil {
+				return err
+			}
+		}
+
+		if current.Status != domain.StatusMerged && !opts.Override {
+			status, err := p.approvalStatus(ctx, current)
+			if err != nil {
+				return err
+			}
+			if err := status.check(); err != nil {
+				return err
+			}
+		}
+
+		pr, err = p.prRepo.MergePullRequest(ctx, ID, opts)
+		if err != nil {
+			if errors.Is(err, prrepo.ErrStatusChanged) {
+				return domain.ErrStatusChanged
+			}
+			return err
+		}
+		merged = current.Status != domain.StatusMerged
+		return nil
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if merged {
+		p.autoMergeChildren(ctx, ID)
+	}
+
+	return pr, nil
+}
+
+func (p *PullRequest) ReassignReviewer(ctx context.Context, prID string, userID string, actorID string) (dto.ReassignResponse, error) {
 	const op = "service.pr.ReassignReviewer"
 
 	// prRepo.GetPullRequestByID (проверка merged, пр не найден)
@@ -102,8 +272,8 @@ func (p *PullRequest) ReassignReviewer(ctx context.Context, prID string, userID
 		}
 		return dto.ReassignResponse{}, errutils.Wrap(op, err)
 	}
-	if pr.Status == "MERGED" {
-		return dto.ReassignResponse{}, errutils.Wrap(op, domain.ErrPullRequestMerged)
+	if pr.Status != domain.StatusOpen {
+		return dto.ReassignResponse{}, errutils.Wrap(op, statusError(pr.Status))
 	}
 
 	// userRepo.GetUserByID (проверка пользователь не найден)
@@ -124,80 +294,734 @@ func (p *PullRequest) ReassignReviewer(ctx context.Context, prID string, userID
 		return dto.ReassignResponse{}, errutils.Wrap(op, domain.ErrUserNotAssignedForPR)
 	}
 
-	// userRepo.GetNewUserIDForPRReview (нет кандидатов)
-	newUserID, err := p.userRepo.GetNewUserIDForPRReview(ctx, prID, userID)
+	actor, err := p.actor(ctx, actorID)
 	if err != nil {
-		if errors.Is(err, userrepo.ErrUserNotFound) {
-			return dto.ReassignResponse{}, errutils.Wrap(op, domain.ErrNoCandidate)
+		return dto.ReassignResponse{}, errutils.Wrap(op, err)
+	}
+
+	// getNewUserIDForPRReview (нет кандидатов) + prRepo.UpdateReviewer
+	var assignment domain.Assignment
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		assignment, err = p.getNewUserIDForPRReview(ctx, pr, userID)
+		if err != nil {
+			return err
 		}
-		return dto.ReassignResponse{}, err
+		return p.prRepo.UpdateReviewer(ctx, prID, userID, assignment, actor)
+	})
+	if err != nil {
+		return dto.ReassignResponse{}, errutils.Wrap(op, err)
 	}
 
-	// prRepo.UpdateReviewer
-	if err = p.prRepo.UpdateReviewer(ctx, prID, userID, newUserID); err != nil {
+	p.autoMerge(ctx, prID)
+
+	// prRepo.GetPR + prRepo.GetReviewers
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
 		return dto.ReassignResponse{}, errutils.Wrap(op, err)
 	}
 
-	// prRepo.GetPR
-	pr, err = p.prRepo.GetPullRequestByID(ctx, prID)
+	return dto.ReassignResponse{
+		PR:           pullRequestResponse(pr),
+		ReplacedBy:   assignment.ReviewerID,
+		FromFallback: assignment.Factors.FromFallback,
+	}, nil
+}
+
+// DeclineReview lets an assigned reviewer step down. The decline is recorded so
+// the user is never picked again for this PR. Without a replacement the seat is
+// left empty and the PR is marked under-staffed.
+func (p *PullRequest) DeclineReview(ctx context.Context, prID, userID, reason string) (dto.DeclineResponse, error) {
+	const op = "service.pr.DeclineReview"
+
+	pr, err := p.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return dto.DeclineResponse{}, errutils.Wrap(op, err)
+	}
+
+	assigned, err := p.prRepo.IsUserAssignedForPR(ctx, prID, userID)
+	if err != nil {
+		return dto.DeclineResponse{}, errutils.Wrap(op, err)
+	}
+	if !assigned {
+		return dto.DeclineResponse{}, errutils.Wrap(op, domain.ErrUserNotAssignedForPR)
+	}
+
+	var assignment domain.Assignment
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.prRepo.DeclineReview(ctx, prID, userID, reason); err != nil {
+			return err
+		}
+
+		assignment, err = p.getNewUserIDForPRReview(ctx, pr, userID)
+		if errors.Is(err, domain.ErrNoCandidate) {
+			if err := p.prRepo.RemoveReviewer(ctx, prID, userID, &userID); err != nil {
+				return err
+			}
+			return p.prRepo.SetUnderStaffed(ctx, prID, true)
+		}
+		if err != nil {
+			return err
+		}
+
+		return p.prRepo.UpdateReviewer(ctx, prID, userID, assignment, &userID)
+	})
+	if err != nil {
+		return dto.DeclineResponse{}, errutils.Wrap(op, err)
+	}
+
+	p.autoMerge(ctx, prID)
+
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.DeclineResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.DeclineResponse{
+		PR:           pullRequestResponse(pr),
+		ReplacedBy:   assignment.ReviewerID,
+		FromFallback: assignment.Factors.FromFallback,
+	}, nil
+}
+
+// AddReviewer assigns a specific extra reviewer. The user must pass the same
+// eligibility checks as an automatically selected one.
+func (p *PullRequest) AddReviewer(ctx context.Context, prID, userID, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.AddReviewer"
+
+	pr, err := p.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	if _, err = p.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		assigned, err := p.prRepo.IsUserAssignedForPR(ctx, prID, userID)
+		if err != nil {
+			return err
+		}
+		if assigned {
+			return domain.ErrReviewerAssigned
+		}
+
+		settings, err := p.authorTeamSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+
+		assignment, eligible, err := p.manualAssignment(ctx, pr, settings, userID)
+		if err != nil {
+			return err
+		}
+		if !eligible {
+			return domain.ErrReviewerNotEligible
+		}
+
+		if err = p.prRepo.AddReviewer(ctx, prID, assignment, actor); err != nil {
+			return err
+		}
+
+		return p.refreshUnderStaffed(ctx, prID, settings)
+	})
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	p.autoMerge(ctx, prID)
+
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+// RemoveReviewer unassigns a reviewer without picking a replacement.
+func (p *PullRequest) RemoveReviewer(ctx context.Context, prID, userID, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.RemoveReviewer"
+
+	pr, err := p.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		assigned, err := p.prRepo.IsUserAssignedForPR(ctx, prID, userID)
+		if err != nil {
+			return err
+		}
+		if !assigned {
+			return domain.ErrUserNotAssignedForPR
+		}
+
+		settings, err := p.authorTeamSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+
+		if err = p.prRepo.RemoveReviewer(ctx, prID, userID, actor); err != nil {
+			return err
+		}
+
+		return p.refreshUnderStaffed(ctx, prID, settings)
+	})
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	p.autoMerge(ctx, prID)
+
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+// SuggestReviewers ranks everyone who could replace oldUserID, or join the PR
+// when oldUserID is empty, in the order automatic selection would try them.
+// The random strategy has no order, its candidates are ranked by ID within
+// each team. Like automatic selection, it skips users on an absence right now.
+// Nothing is written.
+func (p *PullRequest) SuggestReviewers(ctx context.Context, prID, oldUserID string) (dto.SuggestReviewersResponse, error) {
+	const op = "service.pr.SuggestReviewers"
+
+	pr, err := p.getOpenPullRequest(ctx, prID)
+	if err != nil {
+		return dto.SuggestReviewersResponse{}, errutils.Wrap(op, err)
+	}
+
+	if oldUserID != "" {
+		assigned, err := p.prRepo.IsUserAssignedForPR(ctx, prID, oldUserID)
+		if err != nil {
+			return dto.SuggestReviewersResponse{}, errutils.Wrap(op, err)
+		}
+		if !assigned {
+			return dto.SuggestReviewersResponse{}, errutils.Wrap(op, domain.ErrUserNotAssignedForPR)
+		}
+	}
+
+	settings, filter, err := p.replacementFilter(ctx, pr, oldUserID)
+	if err != nil {
+		return dto.SuggestReviewersResponse{}, errutils.Wrap(op, err)
+	}
+
+	ranked, err := p.rankFromTeams(ctx, settings, filter)
+	if err != nil {
+		return dto.SuggestReviewersResponse{}, errutils.Wrap(op, err)
+	}
+
+	teamName, err := p.teamRepo.GetTeamNameByID(ctx, settings.TeamID)
+	if err != nil {
+		return dto.SuggestReviewersResponse{}, errutils.Wrap(op, err)
+	}
+
+	suggestions := make([]dto.SuggestedReviewer, len(ranked))
+	for i, c := range ranked {
+		score := dto.ScoreBreakdown{
+			OpenReviews:    c.OpenReviews,
+			LastAssignedAt: c.LastAssignedAt,
+			TeamName:       teamName,
+			FromFallback:   c.fallbackPos >= 0,
+			MaxOpenReviews: c.MaxOpenReviews,
+		}
+		if score.FromFallback {
+			score.TeamName = settings.FallbackTeams[c.fallbackPos]
+		}
+		if c.MaxOpenReviews != nil {
+			free := *c.MaxOpenReviews - c.OpenReviews
+			score.FreeSlots = &free
+		}
+
+		suggestions[i] = dto.SuggestedReviewer{
+			UserID: c.ID,
+			Rank:   i + 1,
+			Level:  c.Level,
+			Score:  score,
+		}
+	}
+
+	return dto.SuggestReviewersResponse{
+		PullRequestID: prID,
+		ReplacingUser: oldUserID,
+		Candidates:    suggestions,
+	}, nil
+}
+
+// ReassignOpenReviews moves every OPEN review of the user to a replacement
+// picked the same way ReassignReviewer does, on behalf of actorID. PRs without
+// a replacement keep the user assigned and are reported in NoCandidate.
+func (p *PullRequest) ReassignOpenReviews(ctx context.Context, userID, actorID string) (dto.ReviewReassignment, error) {
+	const op = "service.pr.ReassignOpenReviews"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.ReviewReassignment{}, errutils.Wrap(op, err)
+	}
+
+	result := dto.ReviewReassignment{
+		Reassigned:  []dto.ReassignedReview{},
+		NoCandidate: []string{},
+	}
+
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		reviews, err := p.prRepo.GetPRsWhereUserIsReviewer(ctx, userID, domain.ReviewFilter{Status: domain.StatusOpen})
+		if err != nil {
+			return err
+		}
+
+		for _, review := range reviews {
+			pr := review.PullRequest
+
+			assignment, err := p.getNewUserIDForPRReview(ctx, pr, userID)
+			if errors.Is(err, domain.ErrNoCandidate) {
+				result.NoCandidate = append(result.NoCandidate, pr.ID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if err = p.prRepo.UpdateReviewer(ctx, pr.ID, userID, assignment, actor); err != nil {
+				return err
+			}
+			result.Reassigned = append(result.Reassigned, dto.ReassignedReview{
+				PullRequestID: pr.ID,
+				ReplacedBy:    assignment.ReviewerID,
+				FromFallback:  assignment.Factors.FromFallback,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.ReviewReassignment{}, errutils.Wrap(op, err)
+	}
+
+	for _, r := range result.Reassigned {
+		p.autoMerge(ctx, r.PullRequestID)
+	}
+
+	return result, nil
+}
+
+// getNewUserIDForPRReview picks a replacement reviewer from the author's team
+// or its fallback teams and returns the explained assignment for them.
+func (p *PullRequest) getNewUserIDForPRReview(ctx context.Context, pr domain.PullRequest, oldUserID string) (domain.Assignment, error) {
+	settings, filter, err := p.replacementFilter(ctx, pr, oldUserID)
+	if err != nil {
+		return domain.Assignment{}, err
+	}
+
+	_, assignments, err := p.pickFromTeams(ctx, settings, filter, 1, domain.SourceReplacement)
+	if err != nil {
+		return domain.Assignment{}, err
+	}
+	if len(assignments) == 0 {
+		return domain.Assignment{}, domain.ErrNoCandidate
+	}
+
+	setDueAt(settings, assignments)
+	assignment := assignments[0]
+	assignment.Factors.ReplacedUserID = oldUserID
+
+	return assignment, nil
+}
+
+// replacementFilter returns the author's team settings and the filter for a
+// replacement of oldUserID. Current reviewers, the author and anyone who
+// declined the PR are skipped. A senior leaving the PR is replaced by a senior
+// if the quota needs it.
+func (p *PullRequest) replacementFilter(ctx context.Context, pr domain.PullRequest, oldUserID string) (domain.TeamSettings, domain.CandidateFilter, error) {
+	settings, err := p.authorTeamSettings(ctx, pr)
+	if err != nil {
+		return domain.TeamSettings{}, domain.CandidateFilter{}, err
+	}
+
+	reviewers, err := p.prRepo.GetPullRequestReviewers(ctx, pr.ID)
+	if err != nil {
+		return domain.TeamSettings{}, domain.CandidateFilter{}, err
+	}
+
+	decliners, err := p.prRepo.GetDecliners(ctx, pr.ID)
+	if err != nil {
+		return domain.TeamSettings{}, domain.CandidateFilter{}, err
+	}
+
+	filter := domain.CandidateFilter{
+		ExcludeIDs: append(append(slices.Clone(reviewers), decliners...), pr.AuthorID),
+	}
+
+	keepSenior, err := p.mustReplaceWithSenior(ctx, settings, reviewers, oldUserID)
+	if err != nil {
+		return domain.TeamSettings{}, domain.CandidateFilter{}, err
+	}
+	if keepSenior {
+		filter.Levels = domain.LevelsFrom(settings.SeniorLevel)
+	}
+
+	return settings, filter, nil
+}
+
+// authorTeamSettings returns the author's team settings with the label
+// policies of the PR applied.
+func (p *PullRequest) authorTeamSettings(ctx context.Context, pr domain.PullRequest) (domain.TeamSettings, error) {
+	author, err := p.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	labels, err := p.prRepo.GetLabels(ctx, pr.ID)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	settings, _, err := p.labeledSettings(ctx, author.TeamID, labels)
+	return settings, err
+}
+
+// actor checks that the user a request names as its actor exists. An empty
+// actorID means the request names no one.
+func (p *PullRequest) actor(ctx context.Context, actorID string) (*string, error) {
+	if actorID == "" {
+		return nil, nil
+	}
+
+	if _, err := p.userRepo.GetUserByID(ctx, actorID); err != nil {
+		if errors.Is(err, userrepo.ErrUserNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &actorID, nil
+}
+
+// refreshUnderStaffed compares the current reviewer set with the team quota
+// and the groups required by the label policies of the PR.
+func (p *PullRequest) refreshUnderStaffed(ctx context.Context, prID string, settings domain.TeamSettings) error {
+	reviewerIDs, err := p.prRepo.GetPullRequestReviewers(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if len(reviewerIDs) < settings.ReviewerCount {
+		return p.prRepo.SetUnderStaffed(ctx, prID, true)
+	}
+
+	labels, err := p.prRepo.GetLabels(ctx, prID)
+	if err != nil {
+		return err
+	}
+
+	policies, err := p.labelPolicies(ctx, settings.TeamID, labels)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(policies, domain.LabelPolicy.RequiresGroup) {
+		return p.prRepo.SetUnderStaffed(ctx, prID, false)
+	}
+
+	reviewers := make([]domain.Candidate, 0, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		user, err := p.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		reviewers = append(reviewers, domain.Candidate{ID: user.ID, TeamID: user.TeamID})
+	}
+
+	return p.prRepo.SetUnderStaffed(ctx, prID, len(unmetPolicies(policies, reviewers)) > 0)
+}
+
+func (p *PullRequest) getOpenPullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
 	if err != nil {
 		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
-			return dto.ReassignResponse{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+			return domain.PullRequest{}, domain.ErrPullRequestNotFound
 		}
-		return dto.ReassignResponse{}, errutils.Wrap(op, err)
+		return domain.PullRequest{}, err
+	}
+	if pr.Status != domain.StatusOpen {
+		return domain.PullRequest{}, statusError(pr.Status)
+	}
+
+	return pr, nil
+}
+
+func (p *PullRequest) getPullRequestWithReviewers(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return domain.PullRequest{}, domain.ErrPullRequestNotFound
+		}
+		return domain.PullRequest{}, err
 	}
 
-	// prRepo.GetReviewers
 	pr.Reviewers, err = p.prRepo.GetPullRequestReviewers(ctx, prID)
 	if err != nil {
-		return dto.ReassignResponse{}, errutils.Wrap(op, err)
+		return domain.PullRequest{}, err
 	}
 
-	return dto.ReassignResponse{
-		PR: dto.GetPullRequest{
-			ID:        pr.ID,
-			Name:      pr.Name,
-			AuthorID:  pr.AuthorID,
-			Status:    pr.Status,
-			Reviewers: pr.Reviewers,
-		},
-		ReplacedBy: newUserID,
-	}, nil
+	pr.Assignments, err = p.prRepo.GetPullRequestAssignments(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return pr, nil
+}
+
+func pullRequestResponse(pr domain.PullRequest) dto.GetPullRequest {
+	return dto.GetPullRequest{
+		ID:           pr.ID,
+		Name:         pr.Name,
+		AuthorID:     pr.AuthorID,
+		Status:       pr.Status,
+		Reviewers:    pr.Reviewers,
+		UnderStaffed: pr.UnderStaffed,
+		AutoMerge:    pr.AutoMerge,
+		AutoMerged:   pr.AutoMerged,
+		OpenThreads:  pr.OpenThreads,
+		Assignments:  assignmentsResponse(pr.Assignments),
+		Files:        pr.Files,
+		Labels:       pr.Labels,
+		ParentIDs:    pr.ParentIDs,
+		CreatedAt:    pr.CreatedAt,
+		MergedAt:     pr.MergedAt,
+		ClosedAt:     pr.ClosedAt,
+	}
 }
 
-func (p *PullRequest) GetPRsWhereUserIsReviewer(ctx context.Context, userID string) (dto.GetReviewResponse, error) {
+func (p *PullRequest) GetPRsWhereUserIsReviewer(ctx context.Context, req dto.GetReviewRequest) (dto.GetReviewResponse, error) {
 	const op = "service.pr.GetPRsWhereUserIsReviewer"
 
-	prs, err := p.prRepo.GetPRsWhereUserIsReviewer(ctx, userID)
+	cursor, err := pagination.Decode(req.Cursor)
 	if err != nil {
 		return dto.GetReviewResponse{}, errutils.Wrap(op, err)
 	}
 
-	pullRequestsToReturn := make([]struct {
-		ID       string `json:"pull_request_id"`
-		Name     string `json:"pull_request_name"`
-		AuthorID string `json:"author_id"`
-		Status   string `json:"status"`
-	}, len(prs))
+	filter := domain.ReviewFilter{
+		Status: req.Status,
+		Limit:  pagination.Limit(req.Limit) + 1,
+	}
+	if cursor != nil {
+		filter.AfterTime = &cursor.Time
+		filter.AfterID = cursor.ID
+	}
+
+	reviews, err := p.prRepo.GetPRsWhereUserIsReviewer(ctx, req.UserID, filter)
+	if err != nil {
+		return dto.GetReviewResponse{}, errutils.Wrap(op, err)
+	}
+
+	response := dto.GetReviewResponse{UserID: req.UserID}
+
+	// One extra row tells whether another page exists.
+	if len(reviews) == filter.Limit {
+		reviews = reviews[:len(reviews)-1]
+		last := reviews[len(reviews)-1].PullRequest
+		response.NextCursor = pagination.Cursor{Time: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	now := time.Now()
+	response.PullRequests = make([]dto.ReviewItem, len(reviews))
+	for i, review := range reviews {
+		pr := review.PullRequest
+		response.PullRequests[i] = dto.ReviewItem{
+			ID:         pr.ID,
+			Name:       pr.Name,
+			AuthorID:   pr.AuthorID,
+			Status:     pr.Status,
+			AssignedAt: review.AssignedAt,
+			DueAt:      review.DueAt,
+			Overdue:    review.Overdue(now),
+			CreatedAt:  pr.CreatedAt,
+			MergedAt:   pr.MergedAt,
+		}
+	}
+
+	return response, nil
+}
+
+func (p *PullRequest) GetUnderStaffedPullRequests(ctx context.Context, teamName string) (dto.PullRequestsResponse, error) {
+	const op = "service.pr.GetUnderStaffedPullRequests"
+
+	prs, err := p.prRepo.GetUnderStaffedPullRequests(ctx, teamName)
+	if err != nil {
+		return dto.PullRequestsResponse{}, errutils.Wrap(op, err)
+	}
 
+	pullRequests := make([]dto.GetPullRequest, len(prs))
 	for i, pr := range prs {
-		pullRequestsToReturn[i] = struct {
-			ID       string `json:"pull_request_id"`
-			Name     string `json:"pull_request_name"`
-			AuthorID string `json:"author_id"`
-			Status   string `json:"status"`
-		}{
-			ID:       pr.ID,
-			Name:     pr.Name,
-			AuthorID: pr.AuthorID,
-			Status:   pr.Status,
+		pullRequests[i] = dto.GetPullRequest{
+			ID:           pr.ID,
+			Name:         pr.Name,
+			AuthorID:     pr.AuthorID,
+			Status:       pr.Status,
+			Reviewers:    pr.Reviewers,
+			UnderStaffed: pr.UnderStaffed,
 		}
 	}
 
-	response := dto.GetReviewResponse{
-		UserID:       userID,
-		PullRequests: pullRequestsToReturn,
+	return dto.PullRequestsResponse{PullRequests: pullRequests}, nil
+}
+
+func (p *PullRequest) GetPullRequest(ctx context.Context, prID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.GetPullRequest"
+
+	pr, err := p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
 	}
 
-	return response, nil
+	return pullRequestResponse(pr), nil
+}
+
+// RenamePullRequest changes the PR name. Merged PRs are read-only.
+func (p *PullRequest) RenamePullRequest(ctx context.Context, prID, name, actorID string) (dto.GetPullRequest, error) {
+	const op = "service.pr.Rename"
+
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+	if pr.Status == domain.StatusMerged {
+		return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestMerged)
+	}
+
+	if err := p.prRepo.UpdateName(ctx, prID, name, actor); err != nil {
+		if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+			return dto.GetPullRequest{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+		}
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	pr, err = p.getPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+func (p *PullRequest) ListPullRequests(ctx context.Context, req dto.ListPullRequestsRequest) (dto.PullRequestListResponse, error) {
+	const op = "service.pr.ListPullRequests"
+
+	cursor, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return dto.PullRequestListResponse{}, errutils.Wrap(op, err)
+	}
+
+	filter := domain.PullRequestFilter{
+		AuthorID:    req.AuthorID,
+		TeamName:    req.TeamName,
+		Status:      req.Status,
+		ReviewerID:  req.ReviewerID,
+		CreatedFrom: timeOrNil(req.CreatedFrom),
+		CreatedTo:   timeOrNil(req.CreatedTo),
+		MergedFrom:  timeOrNil(req.MergedFrom),
+		MergedTo:    timeOrNil(req.MergedTo),
+		Limit:       pagination.Limit(req.Limit) + 1,
+	}
+	if cursor != nil {
+		filter.AfterTime = &cursor.Time
+		filter.AfterID = cursor.ID
+	}
+
+	prs, err := p.prRepo.ListPullRequests(ctx, filter)
+	if err != nil {
+		return dto.PullRequestListResponse{}, errutils.Wrap(op, err)
+	}
+
+	// One extra row tells whether another page exists.
+	var resp dto.PullRequestListResponse
+	if len(prs) == filter.Limit {
+		prs = prs[:len(prs)-1]
+		last := prs[len(prs)-1]
+		resp.NextCursor = pagination.Cursor{Time: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	resp.PullRequests = make([]dto.GetPullRequest, len(prs))
+	for i, pr := range prs {
+		resp.PullRequests[i] = pullRequestResponse(pr)
+	}
+
+	return resp, nil
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// GetOverdueReviews lists assignments past their SLA due time, optionally for
+// one team or one reviewer. A filter naming an unknown team or user is an
+// error rather than an empty list.
+func (p *PullRequest) GetOverdueReviews(ctx context.Context, teamName, userID string) (dto.OverdueReviewsResponse, error) {
+	const op = "service.pr.GetOverdueReviews"
+
+	if teamName != "" {
+		if _, err := p.teamRepo.GetTeamSettingsByName(ctx, teamName); err != nil {
+			if errors.Is(err, teamrepo.ErrTeamNotFound) {
+				return dto.OverdueReviewsResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+			}
+			return dto.OverdueReviewsResponse{}, errutils.Wrap(op, err)
+		}
+	}
+
+	if userID != "" {
+		if _, err := p.userRepo.GetUserByID(ctx, userID); err != nil {
+			if errors.Is(err, userrepo.ErrUserNotFound) {
+				return dto.OverdueReviewsResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
+			}
+			return dto.OverdueReviewsResponse{}, errutils.Wrap(op, err)
+		}
+	}
+
+	overdue, err := p.prRepo.GetOverdueReviews(ctx, teamName, userID)
+	if err != nil {
+		return dto.OverdueReviewsResponse{}, errutils.Wrap(op, err)
+	}
+
+	now := time.Now()
+	resp := dto.OverdueReviewsResponse{Overdue: make([]dto.OverdueReview, len(overdue))}
+	for i, o := range overdue {
+		resp.Overdue[i] = dto.OverdueReview{
+			PullRequestID:   o.PullRequestID,
+			PullRequestName: o.PullRequestName,
+			TeamName:        o.TeamName,
+			ReviewerID:      o.ReviewerID,
+			AssignedAt:      o.AssignedAt,
+			DueAt:           o.DueAt,
+			OverdueSeconds:  int64(now.Sub(o.DueAt).Seconds()),
+		}
+	}
+
+	return resp, nil
 }
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	prrepo "github.com/ilam072/avito-backend-internship/internal/pullrequest/repo"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"slices"
+)
+
+// AddParents makes a PR that isn't merged yet depend on other PRs. It can't be
+// merged before all of them are.
+func (p *PullRequest) AddParents(ctx context.Context, prID, actorID string, parentIDs []string) (dto.GetPullRequest, error) {
+	const op = "service.pr.AddParents"
+
+	pr, err := p.changeParents(ctx, prID, actorID, parentIDs, p.addParents)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+func (p *PullRequest) RemoveParents(ctx context.Context, prID, actorID string, parentIDs []string) (dto.GetPullRequest, error) {
+	const op = "service.pr.RemoveParents"
+
+	pr, err := p.changeParents(ctx, prID, actorID, parentIDs, p.prRepo.RemoveParents)
+	if err != nil {
+		return dto.GetPullRequest{}, errutils.Wrap(op, err)
+	}
+
+	return pullRequestResponse(pr), nil
+}
+
+func (p *PullRequest) changeParents(
+	ctx context.Context,
+	prID, actorID string,
+	parentIDs []string,
+	change func(ctx context.Context, prID string, parentIDs []string, actorID *string) error,
+) (domain.PullRequest, error) {
+	actor, err := p.actor(ctx, actorID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	err = p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := p.prRepo.GetPullRequestByID(ctx, prID)
+		if err != nil {
+			if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+				return domain.ErrPullRequestNotFound
+			}
+			return err
+		}
+		if pr.Status == domain.StatusMerged {
+			return domain.ErrPullRequestMerged
+		}
+
+		return change(ctx, prID, uniqueIDs(parentIDs), actor)
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	p.autoMerge(ctx, prID)
+
+	return p.getPullRequestWithReviewers(ctx, prID)
+}
+
+func (p *PullRequest) addParents(ctx context.Context, prID string, parentIDs []string, actorID *string) error {
+	err := p.prRepo.AddParents(ctx, prID, parentIDs, actorID)
+	if errors.Is(err, prrepo.ErrPullRequestNotFound) {
+		return domain.ErrPullRequestNotFound
+	}
+	if errors.Is(err, prrepo.ErrDependencyCycle) {
+		return domain.ErrDependencyCycle
+	}
+	return err
+}
+
+// checkParentsMerged rejects merging a PR whose parents aren't all merged.
+func (p *PullRequest) checkParentsMerged(ctx context.Context, prID string) error {
+	parents, err := p.prRepo.GetUnmergedParents(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if len(parents) > 0 {
+		return domain.ErrParentsNotMerged
+	}
+	return nil
+}
+
+// GetStack returns the PRs connected to prID through dependencies, parents
+// before their children.
+func (p *PullRequest) GetStack(ctx context.Context, prID string) (dto.StackResponse, error) {
+	const op = "service.pr.GetStack"
+
+	stack, err := p.prRepo.GetStack(ctx, prID)
+	if err != nil {
+		return dto.StackResponse{}, errutils.Wrap(op, err)
+	}
+	if len(stack) == 0 {
+		return dto.StackResponse{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+	}
+
+	ordered := stackOrder(stack)
+	nodes := make([]dto.StackNode, len(ordered))
+	for i, pr := range ordered {
+		nodes[i] = dto.StackNode{
+			ID:        pr.ID,
+			Name:      pr.Name,
+			AuthorID:  pr.AuthorID,
+			Status:    pr.Status,
+			ParentIDs: pr.ParentIDs,
+			CreatedAt: pr.CreatedAt,
+			MergedAt:  pr.MergedAt,
+		}
+	}
+
+	return dto.StackResponse{
+		PullRequestID: prID,
+		PullRequests:  nodes,
+	}, nil
+}
+
+// stackOrder sorts the PRs topologically, parents first. PRs whose parents are
+// all placed keep their relative order, so an input sorted by age stays
+// sorted where the dependencies allow it.
+func stackOrder(prs []domain.PullRequest) []domain.PullRequest {
+	placed := make(map[string]bool, len(prs))
+	ordered := make([]domain.PullRequest, 0, len(prs))
+
+	for len(ordered) < len(prs) {
+		progress := false
+		for _, pr := range prs {
+			if placed[pr.ID] || !parentsPlaced(pr, placed) {
+				continue
+			}
+			placed[pr.ID] = true
+			ordered = append(ordered, pr)
+			progress = true
+			break
+		}
+
+		// Dependencies are acyclic, but don't loop forever if they aren't.
+		if !progress {
+			for _, pr := range prs {
+				if !placed[pr.ID] {
+					placed[pr.ID] = true
+					ordered = append(ordered, pr)
+				}
+			}
+		}
+	}
+
+	return ordered
+}
+
+func parentsPlaced(pr domain.PullRequest, placed map[string]bool) bool {
+	return !slices.ContainsFunc(pr.ParentIDs, func(id string) bool {
+		return !placed[id]
+	})
+}
+
+func uniqueIDs(ids []string) []string {
+	result := slices.Clone(ids)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
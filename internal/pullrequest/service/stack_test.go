@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStackOrder(t *testing.T) {
+	pr := func(id string, parents ...string) domain.PullRequest {
+		return domain.PullRequest{ID: id, ParentIDs: parents}
+	}
+	ids := func(prs []domain.PullRequest) []string {
+		result := make([]string, len(prs))
+		for i, pr := range prs {
+			result[i] = pr.ID
+		}
+		return result
+	}
+
+	t.Run("KeepsOrderWithoutDependencies", func(t *testing.T) {
+		got := stackOrder([]domain.PullRequest{pr("a"), pr("b"), pr("c")})
+		assert.Equal(t, []string{"a", "b", "c"}, ids(got))
+	})
+
+	t.Run("ParentsFirst", func(t *testing.T) {
+		// c was opened first but sits on top of b, which sits on a.
+		got := stackOrder([]domain.PullRequest{pr("c", "b"), pr("a"), pr("b", "a")})
+		assert.Equal(t, []string{"a", "b", "c"}, ids(got))
+	})
+
+	t.Run("Diamond", func(t *testing.T) {
+		got := stackOrder([]domain.PullRequest{pr("root"), pr("d", "b", "c"), pr("b", "root"), pr("c", "root")})
+		assert.Equal(t, []string{"root", "b", "c", "d"}, ids(got))
+	})
+
+	t.Run("CycleDoesNotHang", func(t *testing.T) {
+		got := stackOrder([]domain.PullRequest{pr("a", "b"), pr("b", "a")})
+		assert.ElementsMatch(t, []string{"a", "b"}, ids(got))
+	})
+}
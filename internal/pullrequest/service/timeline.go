@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/internal/types/dto"
+	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+)
+
+// GetTimeline returns every recorded change of the PR in the order it
+// happened.
+func (p *PullRequest) GetTimeline(ctx context.Context, prID string) (dto.TimelineResponse, error) {
+	const op = "service.pr.GetTimeline"
+
+	exists, err := p.prRepo.PullRequestExists(ctx, prID)
+	if err != nil {
+		return dto.TimelineResponse{}, errutils.Wrap(op, err)
+	}
+	if !exists {
+		return dto.TimelineResponse{}, errutils.Wrap(op, domain.ErrPullRequestNotFound)
+	}
+
+	events, err := p.prRepo.GetEvents(ctx, prID)
+	if err != nil {
+		return dto.TimelineResponse{}, errutils.Wrap(op, err)
+	}
+
+	eventsResp := make([]dto.Event, len(events))
+	for i, e := range events {
+		eventsResp[i] = dto.Event{
+			ID:        e.ID,
+			Kind:      e.Kind,
+			ActorID:   e.ActorID,
+			Before:    e.Before,
+			After:     e.After,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+
+	return dto.TimelineResponse{
+		PullRequestID: prID,
+		Events:        eventsResp,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type Escalator interface {
+	ProcessStaleReviews(ctx context.Context) error
+}
+
+// Worker periodically reminds about and escalates stale reviews until ctx is
+// cancelled. Every step is claimed with a conditional update, so several
+// instances can run at once.
+type Worker struct {
+	escalator Escalator
+	interval  time.Duration
+}
+
+func New(escalator Escalator, interval time.Duration) *Worker {
+	return &Worker{escalator: escalator, interval: interval}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.escalator.ProcessStaleReviews(ctx); err != nil && ctx.Err() == nil {
+			log.Logger.Error().Err(err).Msg("failed to process stale reviews")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
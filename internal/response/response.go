@@ -31,6 +31,10 @@ func Conflict(c *gin.Context, code string, message string) {
 	Error(c, http.StatusConflict, code, message)
 }
 
+func Forbidden(c *gin.Context, message string) {
+	Error(c, http.StatusForbidden, "FORBIDDEN", message)
+}
+
 func NotFound(c *gin.Context) {
 	Error(c, http.StatusConflict, "NOT_FOUND", "resource not found")
 }
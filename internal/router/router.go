@@ -2,6 +2,8 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	absencerest "github.com/ilam072/avito-backend-internship/internal/absence/rest"
+	commentrest "github.com/ilam072/avito-backend-internship/internal/comment/rest"
 	pullrequestrest "github.com/ilam072/avito-backend-internship/internal/pullrequest/rest"
 	teamrest "github.com/ilam072/avito-backend-internship/internal/team/rest"
 	userrest "github.com/ilam072/avito-backend-internship/internal/user/rest"
@@ -11,6 +13,8 @@ func New(
 	userHandler *userrest.UserHandler,
 	teamHandler *teamrest.TeamHandler,
 	prHandler *pullrequestrest.PullRequestHandler,
+	absenceHandler *absencerest.AbsenceHandler,
+	commentHandler *commentrest.CommentHandler,
 ) *gin.Engine {
 	engine := gin.New()
 	engine.Use(gin.Logger())
@@ -19,15 +23,50 @@ func New(
 	// team
 	engine.POST("/team/add", teamHandler.CreateTeam)
 	engine.GET("/team/get", userHandler.GetTeam) // query ?team_name=
+	engine.POST("/team/setSettings", teamHandler.SetTeamSettings)
+	engine.POST("/team/setOwnershipRules", teamHandler.SetOwnershipRules)
+	engine.GET("/team/getOwnershipRules", teamHandler.GetOwnershipRules) // query ?team_name=
+	engine.POST("/team/setLabelPolicies", teamHandler.SetLabelPolicies)
+	engine.GET("/team/getLabelPolicies", teamHandler.GetLabelPolicies) // query ?team_name=
 
 	// users
 	engine.POST("/users/setIsActive", userHandler.SetUserIsActive)
-	engine.GET("/users/getReview", prHandler.GetReview) // query ?user_id=
+	engine.POST("/users/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+	engine.POST("/users/setLevel", userHandler.SetLevel)
+	engine.GET("/users/getReview", prHandler.GetReview)              // query ?user_id=&status=&limit=&cursor= (status, limit, cursor optional)
+	engine.GET("/users/getReviewAsOf", prHandler.GetReviewQueueAsOf) // query ?user_id=&at=
+	engine.POST("/users/addAbsence", absenceHandler.CreateAbsence)
+	engine.GET("/users/getAbsences", absenceHandler.GetAbsences) // query ?user_id=
+	engine.POST("/users/cancelAbsence", absenceHandler.CancelAbsence)
 
 	// pull request
 	engine.POST("/pullRequest/create", prHandler.CreatePullRequest)
 	engine.POST("/pullRequest/merge", prHandler.MergePullRequest)
+	engine.POST("/pullRequest/markReady", prHandler.MarkReady)
+	engine.POST("/pullRequest/close", prHandler.Close)
+	engine.POST("/pullRequest/reopen", prHandler.Reopen)
 	engine.POST("/pullRequest/reassign", prHandler.Reassign)
+	engine.POST("/pullRequest/decline", prHandler.Decline)
+	engine.POST("/pullRequest/addReviewer", prHandler.AddReviewer)
+	engine.POST("/pullRequest/removeReviewer", prHandler.RemoveReviewer)
+	engine.POST("/pullRequest/submitReview", prHandler.SubmitReview)
+	engine.PATCH("/pullRequest/rename", prHandler.Rename)
+	engine.POST("/pullRequest/addLabels", prHandler.AddLabels)
+	engine.POST("/pullRequest/removeLabels", prHandler.RemoveLabels)
+	engine.POST("/pullRequest/addParents", prHandler.AddParents)
+	engine.POST("/pullRequest/removeParents", prHandler.RemoveParents)
+	engine.POST("/pullRequest/setAutoMerge", prHandler.SetAutoMerge)
+	engine.POST("/pullRequest/addComment", commentHandler.CreateComment)
+	engine.POST("/pullRequest/resolveThread", commentHandler.ResolveThread)
+	engine.GET("/pullRequest/get", prHandler.GetPullRequest)                // query ?pull_request_id=
+	engine.GET("/pullRequest/stack", prHandler.GetStack)                    // query ?pull_request_id=
+	engine.GET("/pullRequest/timeline", prHandler.GetTimeline)              // query ?pull_request_id=
+	engine.GET("/pullRequest/reviewersAsOf", prHandler.GetReviewersAsOf)    // query ?pull_request_id=&at=
+	engine.GET("/pullRequest/getComments", commentHandler.GetComments)      // query ?pull_request_id=
+	engine.GET("/pullRequest/list", prHandler.List)                         // query ?author_id=&team_name=&status=&reviewer_id=&created_from=&created_to=&merged_from=&merged_to=&limit=&cursor= (all optional)
+	engine.GET("/pullRequest/underStaffed", prHandler.GetUnderStaffed)      // query ?team_name= (optional)
+	engine.GET("/pullRequest/suggestReviewers", prHandler.SuggestReviewers) // query ?pull_request_id=&old_user_id= (optional)
+	engine.GET("/pullRequest/overdue", prHandler.GetOverdue)                // query ?team_name=&user_id= (both optional)
 
 	return engine
 }
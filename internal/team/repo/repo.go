@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/db"
 	"github.com/ilam072/avito-backend-internship/pkg/errutils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,13 +19,18 @@ func New(db *pgxpool.Pool) *TeamRepo {
 	return &TeamRepo{db: db}
 }
 
+func (r *TeamRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
 var (
 	ErrTeamExists   = errors.New("team exists")
 	ErrTeamNotFound = errors.New("team not found")
+	ErrUserNotFound = errors.New("user not found")
 )
 
-func (r *TeamRepo) CreateTeam(ctx context.Context, name string, users []domain.User) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+func (r *TeamRepo) CreateTeam(ctx context.Context, name string, users []domain.User, settings domain.TeamSettings) error {
+	tx, err := r.conn(ctx).Begin(ctx)
 	if err != nil {
 		return errutils.Wrap("failed to begin transaction", err)
 	}
@@ -45,21 +51,50 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, name string, users []domain.U
 		return errutils.Wrap("failed to create team", err)
 	}
 
+	// An empty level keeps the current one of an existing user.
 	query = `
-        INSERT INTO users (id, name, is_active, team_id)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO users (id, name, is_active, team_id, level)
+        VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'MIDDLE'))
         ON CONFLICT (id) 
         DO UPDATE SET
             name = EXCLUDED.name,
             is_active = EXCLUDED.is_active,
-            team_id = EXCLUDED.team_id; 
+            team_id = EXCLUDED.team_id,
+            level = COALESCE(NULLIF($5, ''), users.level); 
     `
 	for _, u := range users {
-		if _, err := tx.Exec(ctx, query, u.ID, u.Username, u.IsActive, teamID); err != nil {
+		if _, err := tx.Exec(ctx, query, u.ID, u.Username, u.IsActive, teamID, u.Level); err != nil {
 			return errutils.Wrap("failed to create user", err)
 		}
 	}
 
+	if err := checkLead(ctx, tx, teamID, settings.LeadID); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO team_settings (team_id, reviewer_count, min_reviewers, senior_reviewers, senior_level, required_approvals, review_sla_hours, lead_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+	if _, err := tx.Exec(
+		ctx,
+		query,
+		teamID,
+		settings.ReviewerCount,
+		settings.MinReviewers,
+		settings.SeniorReviewers,
+		settings.SeniorLevel,
+		settings.RequiredApprovals,
+		settings.ReviewSLAHours,
+		settings.LeadID,
+	); err != nil {
+		return errutils.Wrap("failed to create team settings", err)
+	}
+
+	if err := replaceFallbacks(ctx, tx, teamID, settings.FallbackTeams); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return errutils.Wrap("failed to commit transaction", err)
 	}
@@ -75,7 +110,7 @@ func (r *TeamRepo) GetTeamNameByID(ctx context.Context, ID int) (string, error)
 	`
 
 	var name string
-	if err := r.db.QueryRow(ctx, query, ID).Scan(&name); err != nil {
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(&name); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return "", errutils.Wrap("failed to get team name", ErrTeamNotFound)
 		}
@@ -84,6 +119,390 @@ func (r *TeamRepo) GetTeamNameByID(ctx context.Context, ID int) (string, error)
 	return name, nil
 }
 
+const teamSettingsColumns = `
+	s.team_id,
+	s.reviewer_count,
+	s.min_reviewers,
+	s.senior_reviewers,
+	s.senior_level,
+	s.required_approvals,
+	s.review_sla_hours,
+	s.lead_id,
+	ARRAY(
+		SELECT f.fallback_team_id
+		FROM team_fallbacks f
+		WHERE f.team_id = s.team_id
+		ORDER BY f.position
+	),
+	ARRAY(
+		SELECT t.name
+		FROM team_fallbacks f
+		JOIN teams t ON t.id = f.fallback_team_id
+		WHERE f.team_id = s.team_id
+		ORDER BY f.position
+	)
+`
+
+func (r *TeamRepo) GetTeamSettings(ctx context.Context, teamID int) (domain.TeamSettings, error) {
+	query := `
+		SELECT ` + teamSettingsColumns + `
+		FROM team_settings s
+		WHERE s.team_id = $1;
+	`
+
+	return scanTeamSettings(r.conn(ctx).QueryRow(ctx, query, teamID))
+}
+
+func (r *TeamRepo) GetTeamSettingsByName(ctx context.Context, name string) (domain.TeamSettings, error) {
+	query := `
+		SELECT ` + teamSettingsColumns + `
+		FROM team_settings s
+		JOIN teams t ON t.id = s.team_id
+		WHERE t.name = $1;
+	`
+
+	return scanTeamSettings(r.conn(ctx).QueryRow(ctx, query, name))
+}
+
+func scanTeamSettings(row pgx.Row) (domain.TeamSettings, error) {
+	var settings domain.TeamSettings
+	if err := row.Scan(
+		&settings.TeamID,
+		&settings.ReviewerCount,
+		&settings.MinReviewers,
+		&settings.SeniorReviewers,
+		&settings.SeniorLevel,
+		&settings.RequiredApprovals,
+		&settings.ReviewSLAHours,
+		&settings.LeadID,
+		&settings.FallbackTeamIDs,
+		&settings.FallbackTeams,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TeamSettings{}, ErrTeamNotFound
+		}
+		return domain.TeamSettings{}, errutils.Wrap("failed to get team settings", err)
+	}
+
+	return settings, nil
+}
+
+func (r *TeamRepo) UpdateTeamSettings(ctx context.Context, settings domain.TeamSettings) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := checkLead(ctx, tx, settings.TeamID, settings.LeadID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE team_settings
+		SET reviewer_count = $1,
+		    min_reviewers = $2,
+		    senior_reviewers = $3,
+		    senior_level = $4,
+		    required_approvals = $5,
+		    review_sla_hours = $6,
+		    lead_id = $7,
+		    updated_at = NOW()
+		WHERE team_id = $8;
+	`
+
+	res, err := tx.Exec(
+		ctx,
+		query,
+		settings.ReviewerCount,
+		settings.MinReviewers,
+		settings.SeniorReviewers,
+		settings.SeniorLevel,
+		settings.RequiredApprovals,
+		settings.ReviewSLAHours,
+		settings.LeadID,
+		settings.TeamID,
+	)
+	if err != nil {
+		return errutils.Wrap("failed to update team settings", err)
+	}
+
+	if rows := res.RowsAffected(); rows == 0 {
+		return ErrTeamNotFound
+	}
+
+	if err := replaceFallbacks(ctx, tx, settings.TeamID, settings.FallbackTeams); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// checkLead makes sure the team lead, if set, is a member of the team.
+func checkLead(ctx context.Context, tx pgx.Tx, teamID int, leadID *string) error {
+	if leadID == nil {
+		return nil
+	}
+
+	var member bool
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND team_id = $2)`
+	if err := tx.QueryRow(ctx, query, *leadID, teamID).Scan(&member); err != nil {
+		return errutils.Wrap("failed to check team lead", err)
+	}
+	if !member {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func replaceFallbacks(ctx context.Context, tx pgx.Tx, teamID int, names []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM team_fallbacks WHERE team_id = $1`, teamID); err != nil {
+		return errutils.Wrap("failed to delete team fallbacks", err)
+	}
+
+	fallbackIDs, err := resolveTeamIDs(ctx, tx, names)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+		VALUES ($1, $2, $3);
+	`
+	for i, fallbackID := range fallbackIDs {
+		if _, err := tx.Exec(ctx, query, teamID, fallbackID, i); err != nil {
+			return errutils.Wrap("failed to insert team fallback", err)
+		}
+	}
+
+	return nil
+}
+
+// resolveTeamIDs returns IDs of the named teams in the same order.
+func resolveTeamIDs(ctx context.Context, tx pgx.Tx, names []string) ([]int, error) {
+	if len(names) == 0 {
+		return []int{}, nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id, name FROM teams WHERE name = ANY($1)`, names)
+	if err != nil {
+		return nil, errutils.Wrap("failed to resolve teams", err)
+	}
+	defer rows.Close()
+
+	idsByName := make(map[string]int, len(names))
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, errutils.Wrap("failed to scan team", err)
+		}
+		idsByName[name] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	ids := make([]int, len(names))
+	for i, name := range names {
+		id, ok := idsByName[name]
+		if !ok {
+			return nil, ErrTeamNotFound
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+func (r *TeamRepo) GetOwnershipRules(ctx context.Context, teamID int) ([]domain.OwnershipRule, error) {
+	query := `
+		SELECT r.pattern,
+		       r.owner_user_ids,
+		       r.owner_team_ids,
+		       ARRAY(SELECT t.name FROM teams t WHERE t.id = ANY(r.owner_team_ids) ORDER BY t.name)
+		FROM ownership_rules r
+		WHERE r.team_id = $1
+		ORDER BY r.position;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, teamID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query ownership rules", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.OwnershipRule
+	for rows.Next() {
+		var rule domain.OwnershipRule
+		if err := rows.Scan(&rule.Pattern, &rule.OwnerUserIDs, &rule.OwnerTeamIDs, &rule.OwnerTeams); err != nil {
+			return nil, errutils.Wrap("failed to scan ownership rule", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return rules, nil
+}
+
+func (r *TeamRepo) ReplaceOwnershipRules(ctx context.Context, teamID int, rules []domain.OwnershipRule) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM ownership_rules WHERE team_id = $1`, teamID); err != nil {
+		return errutils.Wrap("failed to delete ownership rules", err)
+	}
+
+	query := `
+		INSERT INTO ownership_rules (team_id, position, pattern, owner_user_ids, owner_team_ids)
+		VALUES ($1, $2, $3, $4, $5);
+	`
+	for i, rule := range rules {
+		if err := checkUsers(ctx, tx, rule.OwnerUserIDs); err != nil {
+			return err
+		}
+
+		teamIDs, err := resolveTeamIDs(ctx, tx, rule.OwnerTeams)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, query, teamID, i, rule.Pattern, rule.OwnerUserIDs, teamIDs); err != nil {
+			return errutils.Wrap("failed to insert ownership rule", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+func (r *TeamRepo) GetLabelPolicies(ctx context.Context, teamID int) ([]domain.LabelPolicy, error) {
+	query := `
+		SELECT p.label,
+		       p.reviewer_count,
+		       p.min_reviewers,
+		       p.required_approvals,
+		       p.group_user_ids,
+		       p.group_team_ids,
+		       ARRAY(SELECT t.name FROM teams t WHERE t.id = ANY(p.group_team_ids) ORDER BY t.name)
+		FROM label_policies p
+		WHERE p.team_id = $1
+		ORDER BY p.label;
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, teamID)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query label policies", err)
+	}
+	defer rows.Close()
+
+	var policies []domain.LabelPolicy
+	for rows.Next() {
+		var p domain.LabelPolicy
+		if err := rows.Scan(
+			&p.Label,
+			&p.ReviewerCount,
+			&p.MinReviewers,
+			&p.RequiredApprovals,
+			&p.GroupUserIDs,
+			&p.GroupTeamIDs,
+			&p.GroupTeams,
+		); err != nil {
+			return nil, errutils.Wrap("failed to scan label policy", err)
+		}
+		policies = append(policies, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return policies, nil
+}
+
+func (r *TeamRepo) ReplaceLabelPolicies(ctx context.Context, teamID int, policies []domain.LabelPolicy) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return errutils.Wrap("failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM label_policies WHERE team_id = $1`, teamID); err != nil {
+		return errutils.Wrap("failed to delete label policies", err)
+	}
+
+	query := `
+		INSERT INTO label_policies (team_id, label, reviewer_count, min_reviewers, required_approvals, group_user_ids, group_team_ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	for _, p := range policies {
+		if err := checkUsers(ctx, tx, p.GroupUserIDs); err != nil {
+			return err
+		}
+
+		teamIDs, err := resolveTeamIDs(ctx, tx, p.GroupTeams)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(
+			ctx,
+			query,
+			teamID,
+			p.Label,
+			p.ReviewerCount,
+			p.MinReviewers,
+			p.RequiredApprovals,
+			p.GroupUserIDs,
+			teamIDs,
+		); err != nil {
+			return errutils.Wrap("failed to insert label policy", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errutils.Wrap("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// checkUsers makes sure every user in ids exists.
+func checkUsers(ctx context.Context, tx pgx.Tx, ids []string) error {
+	var found int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE id = ANY($1)`, ids).Scan(&found); err != nil {
+		return errutils.Wrap("failed to check users", err)
+	}
+	if found != len(ids) {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func isUniqueViolation(err error) bool {
 	var pqErr *pq.Error
 	return errors.As(err, &pqErr) && pqErr.Code == "23505"
@@ -14,6 +14,11 @@ import (
 
 type Team interface {
 	CreateTeam(ctx context.Context, team dto.TeamWithMembers) (dto.TeamWithMembers, error)
+	SetTeamSettings(ctx context.Context, req dto.SetTeamSettingsRequest) (dto.TeamSettingsResponse, error)
+	SetOwnershipRules(ctx context.Context, req dto.SetOwnershipRulesRequest) (dto.OwnershipRulesResponse, error)
+	GetOwnershipRules(ctx context.Context, name string) (dto.OwnershipRulesResponse, error)
+	SetLabelPolicies(ctx context.Context, req dto.SetLabelPoliciesRequest) (dto.LabelPoliciesResponse, error)
+	GetLabelPolicies(ctx context.Context, name string) (dto.LabelPoliciesResponse, error)
 }
 
 type Validator interface {
@@ -49,6 +54,18 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 			response.Conflict(c, "TEAM_EXISTS", "team_name already exists")
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidTeamSettings) {
+			response.BadRequest(c, "invalid team settings")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLevel) {
+			response.BadRequest(c, "invalid user level")
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
 		log.Logger.Error().Err(err).Any("team", team).Msg("failed to create team")
 		response.InternalServerError(c)
 		return
@@ -56,3 +73,141 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{"team": teamResp})
 }
+
+func (h *TeamHandler) SetTeamSettings(c *gin.Context) {
+	var req dto.SetTeamSettingsRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind team settings json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	settings, err := h.team.SetTeamSettings(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTeamSettings) {
+			response.BadRequest(c, "invalid team settings")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set team settings")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func (h *TeamHandler) SetOwnershipRules(c *gin.Context) {
+	var req dto.SetOwnershipRulesRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind ownership rules json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	rules, err := h.team.SetOwnershipRules(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidOwnershipRule) {
+			response.BadRequest(c, "invalid ownership rule: pattern and at least one owner are required")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set ownership rules")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *TeamHandler) GetOwnershipRules(c *gin.Context) {
+	name := c.Query("team_name")
+	if name == "" {
+		response.BadRequest(c, "missing query param 'team_name'")
+		return
+	}
+
+	rules, err := h.team.GetOwnershipRules(c.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("name", name).Msg("failed to get ownership rules")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *TeamHandler) SetLabelPolicies(c *gin.Context) {
+	var req dto.SetLabelPoliciesRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind label policies json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	policies, err := h.team.SetLabelPolicies(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLabelPolicy) {
+			response.BadRequest(c, "invalid label policy: unique label and at least one override or group member are required")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set label policies")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *TeamHandler) GetLabelPolicies(c *gin.Context) {
+	name := c.Query("team_name")
+	if name == "" {
+		response.BadRequest(c, "missing query param 'team_name'")
+		return
+	}
+
+	policies, err := h.team.GetLabelPolicies(c.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("name", name).Msg("failed to get label policies")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
@@ -7,10 +7,18 @@ import (
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
 	"github.com/ilam072/avito-backend-internship/internal/types/dto"
 	"github.com/ilam072/avito-backend-internship/pkg/errutils"
+	"github.com/ilam072/avito-backend-internship/pkg/glob"
+	"slices"
 )
 
 type TeamRepo interface {
-	CreateTeam(ctx context.Context, name string, users []domain.User) error
+	CreateTeam(ctx context.Context, name string, users []domain.User, settings domain.TeamSettings) error
+	GetTeamSettingsByName(ctx context.Context, name string) (domain.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, settings domain.TeamSettings) error
+	GetOwnershipRules(ctx context.Context, teamID int) ([]domain.OwnershipRule, error)
+	ReplaceOwnershipRules(ctx context.Context, teamID int, rules []domain.OwnershipRule) error
+	GetLabelPolicies(ctx context.Context, teamID int) ([]domain.LabelPolicy, error)
+	ReplaceLabelPolicies(ctx context.Context, teamID int, policies []domain.LabelPolicy) error
 }
 
 type Team struct {
@@ -26,23 +34,304 @@ func (t *Team) CreateTeam(ctx context.Context, team dto.TeamWithMembers) (dto.Te
 
 	users := make([]domain.User, len(team.Members))
 	for i, member := range team.Members {
+		if member.Level != "" && !domain.ValidLevel(member.Level) {
+			return dto.TeamWithMembers{}, errutils.Wrap(op, domain.ErrInvalidLevel)
+		}
 		users[i] = domain.User{
 			ID:       member.ID,
 			Username: member.Username,
 			IsActive: member.IsActive,
+			Level:    member.Level,
 		}
 	}
 
-	if err := t.repo.CreateTeam(ctx, team.TeamName, users); err != nil {
+	settings := domain.DefaultTeamSettings()
+	if team.Settings != nil {
+		settings = applySettings(settings, *team.Settings)
+	}
+	if err := validateSettings(team.TeamName, settings); err != nil {
+		return dto.TeamWithMembers{}, errutils.Wrap(op, err)
+	}
+
+	if err := t.repo.CreateTeam(ctx, team.TeamName, users, settings); err != nil {
 		if errors.Is(err, repo.ErrTeamExists) {
 			return dto.TeamWithMembers{}, errutils.Wrap(op, domain.ErrTeamExists)
 		}
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.TeamWithMembers{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.TeamWithMembers{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
 		return dto.TeamWithMembers{}, errutils.Wrap(op, err)
 	}
 
+	team.Settings = &dto.TeamSettings{
+		ReviewerCount:     &settings.ReviewerCount,
+		MinReviewers:      &settings.MinReviewers,
+		SeniorReviewers:   &settings.SeniorReviewers,
+		SeniorLevel:       &settings.SeniorLevel,
+		RequiredApprovals: &settings.RequiredApprovals,
+		ReviewSLAHours:    settings.ReviewSLAHours,
+		LeadID:            settings.LeadID,
+		FallbackTeams:     settings.FallbackTeams,
+	}
+
 	return team, nil
 }
 
+func (t *Team) SetTeamSettings(ctx context.Context, req dto.SetTeamSettingsRequest) (dto.TeamSettingsResponse, error) {
+	const op = "service.team.SetTeamSettings"
+
+	settings, err := t.repo.GetTeamSettingsByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.TeamSettingsResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		return dto.TeamSettingsResponse{}, errutils.Wrap(op, err)
+	}
+
+	settings = applySettings(settings, req.TeamSettings)
+	if err := validateSettings(req.TeamName, settings); err != nil {
+		return dto.TeamSettingsResponse{}, errutils.Wrap(op, err)
+	}
+
+	if err := t.repo.UpdateTeamSettings(ctx, settings); err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.TeamSettingsResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.TeamSettingsResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.TeamSettingsResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.TeamSettingsResponse{
+		TeamName:          req.TeamName,
+		ReviewerCount:     settings.ReviewerCount,
+		MinReviewers:      settings.MinReviewers,
+		SeniorReviewers:   settings.SeniorReviewers,
+		SeniorLevel:       settings.SeniorLevel,
+		RequiredApprovals: settings.RequiredApprovals,
+		ReviewSLAHours:    settings.ReviewSLAHours,
+		LeadID:            settings.LeadID,
+		FallbackTeams:     settings.FallbackTeams,
+	}, nil
+}
+
+func (t *Team) SetOwnershipRules(ctx context.Context, req dto.SetOwnershipRulesRequest) (dto.OwnershipRulesResponse, error) {
+	const op = "service.team.SetOwnershipRules"
+
+	rules := make([]domain.OwnershipRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		if _, err := glob.Compile(rule.Pattern); err != nil {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrInvalidOwnershipRule)
+		}
+		if len(rule.Users) == 0 && len(rule.Teams) == 0 {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrInvalidOwnershipRule)
+		}
+		rules[i] = domain.OwnershipRule{
+			Pattern:      rule.Pattern,
+			OwnerUserIDs: unique(rule.Users),
+			OwnerTeams:   unique(rule.Teams),
+		}
+	}
+
+	settings, err := t.repo.GetTeamSettingsByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		return dto.OwnershipRulesResponse{}, errutils.Wrap(op, err)
+	}
+
+	if err := t.repo.ReplaceOwnershipRules(ctx, settings.TeamID, rules); err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.OwnershipRulesResponse{}, errutils.Wrap(op, err)
+	}
+
+	return t.GetOwnershipRules(ctx, req.TeamName)
+}
+
+func (t *Team) GetOwnershipRules(ctx context.Context, name string) (dto.OwnershipRulesResponse, error) {
+	const op = "service.team.GetOwnershipRules"
+
+	settings, err := t.repo.GetTeamSettingsByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.OwnershipRulesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		return dto.OwnershipRulesResponse{}, errutils.Wrap(op, err)
+	}
+
+	rules, err := t.repo.GetOwnershipRules(ctx, settings.TeamID)
+	if err != nil {
+		return dto.OwnershipRulesResponse{}, errutils.Wrap(op, err)
+	}
+
+	rulesResp := make([]dto.OwnershipRule, len(rules))
+	for i, rule := range rules {
+		rulesResp[i] = dto.OwnershipRule{
+			Pattern: rule.Pattern,
+			Users:   rule.OwnerUserIDs,
+			Teams:   rule.OwnerTeams,
+		}
+	}
+
+	return dto.OwnershipRulesResponse{
+		TeamName: name,
+		Rules:    rulesResp,
+	}, nil
+}
+
+// SetLabelPolicies replaces the label policies of the team. Labels are
+// normalized the same way labels of pull requests are.
+func (t *Team) SetLabelPolicies(ctx context.Context, req dto.SetLabelPoliciesRequest) (dto.LabelPoliciesResponse, error) {
+	const op = "service.team.SetLabelPolicies"
+
+	policies := make([]domain.LabelPolicy, len(req.Policies))
+	seen := make(map[string]bool, len(req.Policies))
+	for i, policy := range req.Policies {
+		labels, err := domain.NormalizeLabels([]string{policy.Label})
+		if err != nil || seen[labels[0]] {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, domain.ErrInvalidLabelPolicy)
+		}
+		seen[labels[0]] = true
+
+		policies[i] = domain.LabelPolicy{
+			Label:             labels[0],
+			ReviewerCount:     policy.ReviewerCount,
+			MinReviewers:      policy.MinReviewers,
+			RequiredApprovals: policy.RequiredApprovals,
+			GroupUserIDs:      unique(policy.Users),
+			GroupTeams:        unique(policy.Teams),
+		}
+		if err := policies[i].Validate(); err != nil {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, err)
+		}
+	}
+
+	settings, err := t.repo.GetTeamSettingsByName(ctx, req.TeamName)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		return dto.LabelPoliciesResponse{}, errutils.Wrap(op, err)
+	}
+
+	if err := t.repo.ReplaceLabelPolicies(ctx, settings.TeamID, policies); err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
+		}
+		return dto.LabelPoliciesResponse{}, errutils.Wrap(op, err)
+	}
+
+	return t.GetLabelPolicies(ctx, req.TeamName)
+}
+
+func (t *Team) GetLabelPolicies(ctx context.Context, name string) (dto.LabelPoliciesResponse, error) {
+	const op = "service.team.GetLabelPolicies"
+
+	settings, err := t.repo.GetTeamSettingsByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo.ErrTeamNotFound) {
+			return dto.LabelPoliciesResponse{}, errutils.Wrap(op, domain.ErrTeamNotFound)
+		}
+		return dto.LabelPoliciesResponse{}, errutils.Wrap(op, err)
+	}
+
+	policies, err := t.repo.GetLabelPolicies(ctx, settings.TeamID)
+	if err != nil {
+		return dto.LabelPoliciesResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.LabelPoliciesResponse{
+		TeamName: name,
+		Policies: labelPoliciesResponse(policies),
+	}, nil
+}
+
+func labelPoliciesResponse(policies []domain.LabelPolicy) []dto.LabelPolicy {
+	resp := make([]dto.LabelPolicy, len(policies))
+	for i, p := range policies {
+		resp[i] = dto.LabelPolicy{
+			Label:             p.Label,
+			ReviewerCount:     p.ReviewerCount,
+			MinReviewers:      p.MinReviewers,
+			RequiredApprovals: p.RequiredApprovals,
+			Users:             p.GroupUserIDs,
+			Teams:             p.GroupTeams,
+		}
+	}
+	return resp
+}
+
+// applySettings overrides only the fields present in the request.
+func applySettings(settings domain.TeamSettings, patch dto.TeamSettings) domain.TeamSettings {
+	if patch.ReviewerCount != nil {
+		settings.ReviewerCount = *patch.ReviewerCount
+	}
+	if patch.MinReviewers != nil {
+		settings.MinReviewers = *patch.MinReviewers
+	}
+	if patch.SeniorReviewers != nil {
+		settings.SeniorReviewers = *patch.SeniorReviewers
+	}
+	if patch.SeniorLevel != nil {
+		settings.SeniorLevel = *patch.SeniorLevel
+	}
+	if patch.RequiredApprovals != nil {
+		settings.RequiredApprovals = *patch.RequiredApprovals
+	}
+	if patch.ReviewSLAHours != nil {
+		settings.ReviewSLAHours = patch.ReviewSLAHours
+		if *patch.ReviewSLAHours == 0 {
+			settings.ReviewSLAHours = nil
+		}
+	}
+	if patch.LeadID != nil {
+		settings.LeadID = patch.LeadID
+		if *patch.LeadID == "" {
+			settings.LeadID = nil
+		}
+	}
+	if patch.FallbackTeams != nil {
+		settings.FallbackTeams = uniqueOrdered(patch.FallbackTeams)
+	}
+	return settings
+}
+
+func validateSettings(teamName string, settings domain.TeamSettings) error {
+	if slices.Contains(settings.FallbackTeams, teamName) {
+		return domain.ErrInvalidTeamSettings
+	}
+	return settings.Validate()
+}
+
+func unique(values []string) []string {
+	result := append([]string{}, values...)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+func uniqueOrdered(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 /*func (t *Team) GetTeam(ctx context.Context, name string) (dto.TeamWithMembers, error) {
 	const op = "service.team.Create"
 
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	AbsenceScheduled = "SCHEDULED"
+	AbsenceActive    = "ACTIVE"
+	AbsenceFinished  = "FINISHED"
+	AbsenceCancelled = "CANCELLED"
+)
+
+type Absence struct {
+	ID        int64
+	UserID    string
+	CreatedBy string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	Status    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	StrategyManual     = "manual"
+	StrategyEscalation = "escalation"
+
+	SourceOwnership   = "ownership"
+	SourceSeniority   = "senior_policy"
+	SourceTeam        = "team"
+	SourceReplacement = "replacement"
+	SourceManual      = "manual"
+	SourceEscalation  = "escalation"
+	SourceLabel       = "label"
+)
+
+// Assignment explains why a reviewer was put on a pull request: the strategy
+// that picked them, how many candidates it chose from and what decided it.
+type Assignment struct {
+	ReviewerID string
+	AssignedAt time.Time
+	DueAt      *time.Time
+	Strategy   string
+	PoolSize   int
+	Factors    AssignmentFactors
+}
+
+type AssignmentFactors struct {
+	Source         string
+	Rule           string
+	FromFallback   bool
+	TeamID         int
+	Level          string
+	OpenReviews    int
+	LastAssignedAt *time.Time
+	ReplacedUserID string
+}
+
+// AssignmentPeriod is the time a reviewer spent assigned to a PR. ValidTo is
+// empty while the assignment lasts. Only ID, Name and AuthorID of the PR are
+// filled.
+type AssignmentPeriod struct {
+	PullRequest PullRequest
+	ReviewerID  string
+	ValidFrom   time.Time
+	ValidTo     *time.Time
+}
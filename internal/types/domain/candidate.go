@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+type Candidate struct {
+	ID             string
+	TeamID         int
+	Level          string
+	OpenReviews    int
+	MaxOpenReviews *int
+	LastAssignedAt *time.Time
+}
+
+// CandidateFilter selects active members of any of TeamIDs plus the users
+// listed in UserIDs, skipping ExcludeIDs. A non-empty Levels keeps only users
+// at one of those levels. Users on an absence right now or without free review
+// capacity are never candidates.
+type CandidateFilter struct {
+	TeamIDs    []int
+	UserIDs    []string
+	ExcludeIDs []string
+	Levels     []string
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+)
+
+// Thread is a discussion on a PR. Its first comment opens it, the rest are
+// replies in the order they were written.
+type Thread struct {
+	ID            int64
+	PullRequestID string
+	CreatedBy     string
+	Comments      []Comment
+	ResolvedBy    *string
+	ResolvedAt    *time.Time
+	CreatedAt     time.Time
+}
+
+func (t Thread) Resolved() bool {
+	return t.ResolvedAt != nil
+}
+
+type Comment struct {
+	ID        int64
+	ThreadID  int64
+	AuthorID  string
+	Body      string
+	CreatedAt time.Time
+}
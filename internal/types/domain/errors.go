@@ -9,6 +9,29 @@ var (
 	ErrPullRequestExists    = errors.New("pull requests exists")
 	ErrPullRequestNotFound  = errors.New("pull request not found")
 	ErrPullRequestMerged    = errors.New("pull request merged")
+	ErrPullRequestDraft     = errors.New("pull request is a draft")
+	ErrPullRequestOpen      = errors.New("pull request is open")
+	ErrPullRequestClosed    = errors.New("pull request closed")
+	ErrStatusChanged        = errors.New("pull request status changed concurrently")
 	ErrUserNotAssignedForPR = errors.New("user isn't assigned for pr")
 	ErrNoCandidate          = errors.New("no active candidate for pr")
+	ErrInvalidTeamSettings  = errors.New("invalid team settings")
+	ErrNotEnoughReviewers   = errors.New("not enough reviewers for pr")
+	ErrInvalidOwnershipRule = errors.New("invalid ownership rule")
+	ErrAbsenceNotFound      = errors.New("absence not found")
+	ErrAbsenceOverlap       = errors.New("absence overlaps another one")
+	ErrAbsenceEnded         = errors.New("absence already ended")
+	ErrForbidden            = errors.New("action is not allowed for this user")
+	ErrReviewerAssigned     = errors.New("reviewer is already assigned for pr")
+	ErrReviewerNotEligible  = errors.New("user can't review this pr")
+	ErrInvalidLevel         = errors.New("invalid user level")
+	ErrNotEnoughSeniors     = errors.New("not enough senior reviewers for pr")
+	ErrNotApproved          = errors.New("pull request lacks required approvals")
+	ErrChangesRequested     = errors.New("pull request has outstanding change requests")
+	ErrInvalidLabel         = errors.New("invalid label")
+	ErrInvalidLabelPolicy   = errors.New("invalid label policy")
+	ErrDependencyCycle      = errors.New("pull request dependencies form a cycle")
+	ErrParentsNotMerged     = errors.New("pull request has unmerged parents")
+	ErrThreadNotFound       = errors.New("comment thread not found")
+	ErrThreadResolved       = errors.New("comment thread is resolved")
 )
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventCreated          = "CREATED"
+	EventAssigned         = "ASSIGNED"
+	EventReassigned       = "REASSIGNED"
+	EventUnassigned       = "UNASSIGNED"
+	EventDeclined         = "DECLINED"
+	EventStatusChanged    = "STATUS_CHANGED"
+	EventMerged           = "MERGED"
+	EventVerdict          = "VERDICT"
+	EventRenamed          = "RENAMED"
+	EventLabelsAdded      = "LABELS_ADDED"
+	EventLabelsRemoved    = "LABELS_REMOVED"
+	EventParentsAdded     = "PARENTS_ADDED"
+	EventParentsRemoved   = "PARENTS_REMOVED"
+	EventAutoMergeChanged = "AUTO_MERGE_CHANGED"
+)
+
+// Event is an entry of the PR timeline. Before and After hold the changed
+// values as JSON and are empty when there was nothing before or after, e.g. for
+// a new assignment or a removed one. ActorID is the user behind the change: the
+// author on creation, the reviewer for verdicts and declines, the actor_id of
+// the request otherwise. It is empty for changes the service makes on its own,
+// like automatic reviewer picks, escalations and auto-merges, and for requests
+// that don't name an actor.
+type Event struct {
+	ID            int64
+	PullRequestID string
+	Kind          string
+	ActorID       *string
+	Before        json.RawMessage
+	After         json.RawMessage
+	CreatedAt     time.Time
+}
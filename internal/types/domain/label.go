@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+const MaxLabelLength = 50
+
+// LabelPolicy overrides the team settings for PRs carrying Label. A policy
+// with a group requires at least one reviewer among GroupUserIDs or members of
+// GroupTeamIDs.
+type LabelPolicy struct {
+	Label             string
+	ReviewerCount     *int
+	MinReviewers      *int
+	RequiredApprovals *int
+	GroupUserIDs      []string
+	GroupTeamIDs      []int
+	GroupTeams        []string
+}
+
+func (p LabelPolicy) Validate() error {
+	if !validLabel(p.Label) {
+		return ErrInvalidLabelPolicy
+	}
+	for _, v := range []*int{p.ReviewerCount, p.MinReviewers, p.RequiredApprovals} {
+		if v != nil && (*v < 0 || *v > MaxReviewerCount) {
+			return ErrInvalidLabelPolicy
+		}
+	}
+	if p.ReviewerCount != nil && p.MinReviewers != nil && *p.MinReviewers > *p.ReviewerCount {
+		return ErrInvalidLabelPolicy
+	}
+	if p.ReviewerCount == nil && p.MinReviewers == nil && p.RequiredApprovals == nil && !p.RequiresGroup() {
+		return ErrInvalidLabelPolicy
+	}
+	return nil
+}
+
+func (p LabelPolicy) RequiresGroup() bool {
+	return len(p.GroupUserIDs) > 0 || len(p.GroupTeamIDs) > 0 || len(p.GroupTeams) > 0
+}
+
+func (p LabelPolicy) InGroup(c Candidate) bool {
+	return slices.Contains(p.GroupUserIDs, c.ID) || slices.Contains(p.GroupTeamIDs, c.TeamID)
+}
+
+// WithLabelPolicies returns the settings with the overrides of policies
+// applied. When several policies set the same field the largest value wins.
+// Quotas that don't fit a lowered reviewer count are capped to it.
+func (s TeamSettings) WithLabelPolicies(policies []LabelPolicy) TeamSettings {
+	var reviewerCount, minReviewers, approvals *int
+	for _, p := range policies {
+		reviewerCount = largest(reviewerCount, p.ReviewerCount)
+		minReviewers = largest(minReviewers, p.MinReviewers)
+		approvals = largest(approvals, p.RequiredApprovals)
+	}
+
+	if reviewerCount != nil {
+		s.ReviewerCount = *reviewerCount
+		s.MinReviewers = min(s.MinReviewers, s.ReviewerCount)
+		s.SeniorReviewers = min(s.SeniorReviewers, s.ReviewerCount)
+		s.RequiredApprovals = min(s.RequiredApprovals, s.ReviewerCount)
+	}
+	if minReviewers != nil {
+		s.MinReviewers = min(*minReviewers, s.ReviewerCount)
+	}
+	if approvals != nil {
+		s.RequiredApprovals = *approvals
+	}
+
+	return s
+}
+
+// NormalizeLabels lowercases and trims labels and returns them sorted without
+// duplicates.
+func NormalizeLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if !validLabel(label) {
+			return nil, ErrInvalidLabel
+		}
+		normalized = append(normalized, label)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+func validLabel(label string) bool {
+	return label != "" && len(label) <= MaxLabelLength && label == strings.ToLower(strings.TrimSpace(label))
+}
+
+func largest(a, b *int) *int {
+	if b == nil || (a != nil && *a >= *b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithLabelPolicies(t *testing.T) {
+	n := func(v int) *int { return &v }
+
+	base := DefaultTeamSettings()
+	base.ReviewerCount = 3
+	base.MinReviewers = 2
+	base.SeniorReviewers = 2
+	base.RequiredApprovals = 2
+
+	t.Run("NoPolicies", func(t *testing.T) {
+		assert.Equal(t, base, base.WithLabelPolicies(nil))
+	})
+
+	t.Run("LoweredCountCapsQuotas", func(t *testing.T) {
+		got := base.WithLabelPolicies([]LabelPolicy{{Label: "hotfix", ReviewerCount: n(1)}})
+		assert.Equal(t, 1, got.ReviewerCount)
+		assert.Equal(t, 1, got.MinReviewers)
+		assert.Equal(t, 1, got.SeniorReviewers)
+		assert.Equal(t, 1, got.RequiredApprovals)
+	})
+
+	t.Run("LargestWins", func(t *testing.T) {
+		got := base.WithLabelPolicies([]LabelPolicy{
+			{Label: "hotfix", ReviewerCount: n(1), RequiredApprovals: n(1)},
+			{Label: "security", ReviewerCount: n(4)},
+		})
+		assert.Equal(t, 4, got.ReviewerCount)
+		assert.Equal(t, 2, got.MinReviewers)
+		assert.Equal(t, 1, got.RequiredApprovals)
+	})
+
+	t.Run("MinReviewersFitCount", func(t *testing.T) {
+		got := base.WithLabelPolicies([]LabelPolicy{{Label: "big", MinReviewers: n(5)}})
+		assert.Equal(t, 3, got.MinReviewers)
+	})
+}
+
+func TestLabelPolicyValidate(t *testing.T) {
+	n := func(v int) *int { return &v }
+
+	assert.NoError(t, LabelPolicy{Label: "hotfix", ReviewerCount: n(1)}.Validate())
+	assert.NoError(t, LabelPolicy{Label: "security", GroupTeams: []string{"sec"}}.Validate())
+
+	assert.ErrorIs(t, LabelPolicy{Label: "empty"}.Validate(), ErrInvalidLabelPolicy)
+	assert.ErrorIs(t, LabelPolicy{Label: "Hotfix", ReviewerCount: n(1)}.Validate(), ErrInvalidLabelPolicy)
+	assert.ErrorIs(t, LabelPolicy{Label: "big", ReviewerCount: n(MaxReviewerCount + 1)}.Validate(), ErrInvalidLabelPolicy)
+	assert.ErrorIs(t, LabelPolicy{Label: "odd", ReviewerCount: n(1), MinReviewers: n(2)}.Validate(), ErrInvalidLabelPolicy)
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	labels, err := NormalizeLabels([]string{" Security", "hotfix", "security"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hotfix", "security"}, labels)
+
+	_, err = NormalizeLabels([]string{"  "})
+	assert.ErrorIs(t, err, ErrInvalidLabel)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	NotificationReviewReminder   = "REVIEW_REMINDER"
+	NotificationReviewEscalation = "REVIEW_ESCALATION"
+)
+
+type Notification struct {
+	Kind          string
+	RecipientID   string
+	PullRequestID string
+	Message       string
+}
+
+// StaleReview is an assignment on an open PR whose reviewer hasn't submitted
+// a verdict for too long.
+type StaleReview struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	ReviewerID      string
+	AssignedAt      time.Time
+}
@@ -4,12 +4,73 @@ import (
 	"time"
 )
 
+const (
+	StatusDraft  = "DRAFT"
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+)
+
 type PullRequest struct {
-	ID        string
-	AuthorID  string
-	Name      string
+	ID            string
+	AuthorID      string
+	Name          string
+	Status        string
+	Files         []string
+	Labels        []string
+	ParentIDs     []string
+	Reviewers     []string
+	Assignments   []Assignment
+	UnderStaffed  bool
+	AutoMerge     bool
+	MergeOverride bool
+	MergedBy      *string
+	AutoMerged    bool
+	OpenThreads   bool
+	CreatedAt     time.Time
+	MergedAt      *time.Time
+	ClosedAt      *time.Time
+}
+
+// MergeOptions describes how a PR gets merged. Override skips the approval
+// check, Automatic marks merges done by the service for an auto-merge PR.
+type MergeOptions struct {
+	Override  bool
+	MergedBy  *string
+	Automatic bool
+}
+
+// PullRequestFilter narrows a PR listing ordered by creation time, newest
+// first. Empty fields don't filter. AfterTime and AfterID continue the listing
+// after the given PR.
+type PullRequestFilter struct {
+	AuthorID    string
+	TeamName    string
+	Status      string
+	ReviewerID  string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	AfterTime   *time.Time
+	AfterID     string
+	Limit       int
+}
+
+// ReviewAssignment is a PR seen from one of its reviewers. Reviewed is set
+// once the reviewer submitted a verdict.
+type ReviewAssignment struct {
+	PullRequest PullRequest
+	AssignedAt  time.Time
+	DueAt       *time.Time
+	Reviewed    bool
+}
+
+// ReviewFilter narrows the PRs of a reviewer ordered by creation time, newest
+// first. Zero Limit returns all of them.
+type ReviewFilter struct {
 	Status    string
-	Reviewers []string
-	CreatedAt time.Time
-	MergedAt  *time.Time
+	AfterTime *time.Time
+	AfterID   string
+	Limit     int
 }
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	VerdictApproved         = "APPROVED"
+	VerdictChangesRequested = "CHANGES_REQUESTED"
+	VerdictCommented        = "COMMENTED"
+)
+
+// Review is the latest verdict of a reviewer on a pull request. A COMMENTED
+// submission never replaces an earlier APPROVED or CHANGES_REQUESTED verdict.
+type Review struct {
+	PullRequestID string
+	ReviewerID    string
+	Verdict       string
+	Comment       *string
+	SubmittedAt   time.Time
+}
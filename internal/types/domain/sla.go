@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+)
+
+const MaxReviewSLAHours = 24 * 30
+
+// WorkingDueAt returns the moment a budget of working time counted from start
+// runs out. Saturdays and Sundays (UTC) don't count, so a day-long budget
+// started on Friday afternoon ends on Monday afternoon.
+func WorkingDueAt(start time.Time, budget time.Duration) time.Time {
+	t := start.UTC()
+	for {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		switch t.Weekday() {
+		case time.Saturday:
+			t = day.AddDate(0, 0, 2)
+			continue
+		case time.Sunday:
+			t = day.AddDate(0, 0, 1)
+			continue
+		}
+
+		endOfDay := day.AddDate(0, 0, 1)
+		left := endOfDay.Sub(t)
+		if budget <= left {
+			return t.Add(budget)
+		}
+		budget -= left
+		t = endOfDay
+	}
+}
+
+// ReviewDueAt returns when a review assigned at assignedAt must start, nil if
+// the team has no review SLA.
+func (s TeamSettings) ReviewDueAt(assignedAt time.Time) *time.Time {
+	if s.ReviewSLAHours == nil {
+		return nil
+	}
+	due := WorkingDueAt(assignedAt, time.Duration(*s.ReviewSLAHours)*time.Hour)
+	return &due
+}
+
+// OverdueReview is an assignment whose reviewer hasn't submitted a verdict
+// before its due time.
+type OverdueReview struct {
+	PullRequestID   string
+	PullRequestName string
+	TeamName        string
+	ReviewerID      string
+	AssignedAt      time.Time
+	DueAt           time.Time
+}
+
+// Overdue reports whether the reviewer of an open PR missed the due time
+// without submitting a verdict.
+func (a ReviewAssignment) Overdue(now time.Time) bool {
+	return a.PullRequest.Status == StatusOpen && !a.Reviewed && a.DueAt != nil && a.DueAt.Before(now)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWorkingDueAt(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		// March 2025: the 3rd is a Monday.
+		return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		start  time.Time
+		budget time.Duration
+		want   time.Time
+	}{
+		{"SameDay", at(3, 9), 4 * time.Hour, at(3, 13)},
+		{"NextWorkingDay", at(4, 15), 24 * time.Hour, at(5, 15)},
+		{"OverWeekend", at(7, 15), 24 * time.Hour, at(10, 15)},
+		{"StartOnSaturday", at(8, 10), 2 * time.Hour, at(10, 2)},
+		{"StartOnSunday", at(9, 23), 48 * time.Hour, at(12, 0)},
+		{"ZeroBudget", at(5, 12), 0, at(5, 12)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WorkingDueAt(tt.start, tt.budget))
+		})
+	}
+}
+
+func TestReviewAssignmentOverdue(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	open := PullRequest{Status: StatusOpen}
+
+	assert.True(t, ReviewAssignment{PullRequest: open, DueAt: &past}.Overdue(now))
+	assert.False(t, ReviewAssignment{PullRequest: open, DueAt: &future}.Overdue(now))
+	assert.False(t, ReviewAssignment{PullRequest: open, DueAt: &past, Reviewed: true}.Overdue(now))
+	assert.False(t, ReviewAssignment{PullRequest: open}.Overdue(now))
+	assert.False(t, ReviewAssignment{PullRequest: PullRequest{Status: StatusMerged}, DueAt: &past}.Overdue(now))
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "slices"
+
+const (
+	DefaultReviewerCount   = 2
+	DefaultMinReviewers    = 0
+	DefaultSeniorReviewers = 0
+	DefaultSeniorLevel     = LevelSenior
+	DefaultApprovals       = 1
+	MaxReviewerCount       = 10
+)
+
+type TeamSettings struct {
+	TeamID            int
+	ReviewerCount     int
+	MinReviewers      int
+	SeniorReviewers   int
+	SeniorLevel       string
+	RequiredApprovals int
+	ReviewSLAHours    *int
+	LeadID            *string
+	FallbackTeamIDs   []int
+	FallbackTeams     []string
+}
+
+func DefaultTeamSettings() TeamSettings {
+	return TeamSettings{
+		ReviewerCount:     DefaultReviewerCount,
+		MinReviewers:      DefaultMinReviewers,
+		SeniorReviewers:   DefaultSeniorReviewers,
+		SeniorLevel:       DefaultSeniorLevel,
+		RequiredApprovals: DefaultApprovals,
+	}
+}
+
+func (s TeamSettings) Validate() error {
+	if s.ReviewerCount < 0 || s.ReviewerCount > MaxReviewerCount {
+		return ErrInvalidTeamSettings
+	}
+	if s.MinReviewers < 0 || s.MinReviewers > s.ReviewerCount {
+		return ErrInvalidTeamSettings
+	}
+	if s.SeniorReviewers < 0 || s.SeniorReviewers > s.ReviewerCount || !ValidLevel(s.SeniorLevel) {
+		return ErrInvalidTeamSettings
+	}
+	if s.RequiredApprovals < 0 || s.RequiredApprovals > MaxReviewerCount {
+		return ErrInvalidTeamSettings
+	}
+	if s.ReviewSLAHours != nil && (*s.ReviewSLAHours <= 0 || *s.ReviewSLAHours > MaxReviewSLAHours) {
+		return ErrInvalidTeamSettings
+	}
+	return nil
+}
+
+// IsSenior reports whether level counts towards the senior reviewers quota.
+func (s TeamSettings) IsSenior(level string) bool {
+	return slices.Contains(LevelsFrom(s.SeniorLevel), level)
+}
+
+type OwnershipRule struct {
+	Pattern      string
+	OwnerUserIDs []string
+	OwnerTeamIDs []int
+	OwnerTeams   []string
+}
+
+func (r OwnershipRule) Owns(c Candidate) bool {
+	for _, id := range r.OwnerUserIDs {
+		if id == c.ID {
+			return true
+		}
+	}
+	for _, teamID := range r.OwnerTeamIDs {
+		if teamID == c.TeamID {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,40 @@
 package domain
 
 import (
+	"slices"
 	"time"
 )
 
+const (
+	LevelJunior = "JUNIOR"
+	LevelMiddle = "MIDDLE"
+	LevelSenior = "SENIOR"
+	LevelLead   = "LEAD"
+)
+
+// Levels lists seniority levels from the lowest to the highest.
+var Levels = []string{LevelJunior, LevelMiddle, LevelSenior, LevelLead}
+
+func ValidLevel(level string) bool {
+	return slices.Contains(Levels, level)
+}
+
+// LevelsFrom returns level together with every level above it.
+func LevelsFrom(level string) []string {
+	i := slices.Index(Levels, level)
+	if i < 0 {
+		return nil
+	}
+	return slices.Clone(Levels[i:])
+}
+
 type User struct {
-	ID        string
-	TeamID    int
-	Username  string
-	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             string
+	TeamID         int
+	Username       string
+	IsActive       bool
+	Level          string
+	MaxOpenReviews *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+)
+
+type CreateAbsenceRequest struct {
+	UserID    string    `json:"user_id" validate:"required"`
+	CreatedBy string    `json:"created_by" validate:"required"`
+	StartsAt  time.Time `json:"starts_at" validate:"required"`
+	EndsAt    time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Reason    string    `json:"reason"`
+}
+
+type CancelAbsenceRequest struct {
+	AbsenceID int64  `json:"absence_id" validate:"required"`
+	ActorID   string `json:"actor_id" validate:"required"`
+}
+
+type Absence struct {
+	ID        int64     `json:"absence_id"`
+	UserID    string    `json:"user_id"`
+	CreatedBy string    `json:"created_by"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+}
+
+type AbsencesResponse struct {
+	UserID   string    `json:"user_id"`
+	Absences []Absence `json:"absences"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+)
+
+type CreateCommentRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	AuthorID      string `json:"author_id" validate:"required"`
+	ThreadID      int64  `json:"thread_id" validate:"omitempty,gt=0"`
+	Body          string `json:"body" validate:"required,max=10000"`
+}
+
+type ResolveThreadRequest struct {
+	ThreadID int64  `json:"thread_id" validate:"required"`
+	ActorID  string `json:"actor_id" validate:"required"`
+}
+
+type Comment struct {
+	ID        int64     `json:"comment_id"`
+	ThreadID  int64     `json:"thread_id"`
+	AuthorID  string    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Thread struct {
+	ID         int64      `json:"thread_id"`
+	CreatedBy  string     `json:"created_by"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy *string    `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Comments   []Comment  `json:"comments"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CommentsResponse struct {
+	PullRequestID string   `json:"pull_request_id"`
+	OpenThreads   bool     `json:"has_unresolved_threads"`
+	Threads       []Thread `json:"threads"`
+}
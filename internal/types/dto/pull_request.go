@@ -1,52 +1,335 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type CreatePullRequest struct {
-	ID       string `json:"pull_request_id" validate:"required"`
-	Name     string `json:"pull_request_name" validate:"required"`
-	AuthorID string `json:"author_id" validate:"required"`
+	ID        string   `json:"pull_request_id" validate:"required"`
+	Name      string   `json:"pull_request_name" validate:"required"`
+	AuthorID  string   `json:"author_id" validate:"required"`
+	Files     []string `json:"changed_files,omitempty"`
+	Labels    []string `json:"labels,omitempty" validate:"dive,required,max=50"`
+	ParentIDs []string `json:"parent_ids,omitempty" validate:"dive,required"`
+	Draft     bool     `json:"draft"`
 }
 
 type GetPullRequest struct {
-	ID        string   `json:"pull_request_id"`
-	Name      string   `json:"pull_request_name"`
-	AuthorID  string   `json:"author_id"`
-	Status    string   `json:"status"`
-	Reviewers []string `json:"assigned_reviewers"`
+	ID              string        `json:"pull_request_id"`
+	Name            string        `json:"pull_request_name"`
+	AuthorID        string        `json:"author_id"`
+	Status          string        `json:"status"`
+	Reviewers       []string      `json:"assigned_reviewers"`
+	UnderStaffed    bool          `json:"under_staffed"`
+	AutoMerge       bool          `json:"auto_merge"`
+	AutoMerged      bool          `json:"auto_merged,omitempty"`
+	OpenThreads     bool          `json:"has_unresolved_threads"`
+	Fallback        []string      `json:"fallback_reviewers,omitempty"`
+	MatchedRules    []MatchedRule `json:"matched_rules,omitempty"`
+	AppliedPolicies []LabelPolicy `json:"applied_label_policies,omitempty"`
+	UnmetPolicies   []LabelPolicy `json:"unmet_label_policies,omitempty"`
+	Assignments     []Assignment  `json:"assignments,omitempty"`
+	Files           []string      `json:"changed_files,omitempty"`
+	Labels          []string      `json:"labels,omitempty"`
+	ParentIDs       []string      `json:"parent_ids,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	MergedAt        *time.Time    `json:"merged_at,omitempty"`
+	ClosedAt        *time.Time    `json:"closed_at,omitempty"`
+}
+
+type Assignment struct {
+	ReviewerID string            `json:"reviewer_id"`
+	AssignedAt time.Time         `json:"assigned_at"`
+	DueAt      *time.Time        `json:"due_at,omitempty"`
+	Strategy   string            `json:"strategy"`
+	PoolSize   int               `json:"pool_size"`
+	Factors    AssignmentFactors `json:"factors"`
+}
+
+type AssignmentFactors struct {
+	Source         string     `json:"source"`
+	Rule           string     `json:"rule,omitempty"`
+	FromFallback   bool       `json:"from_fallback"`
+	TeamID         int        `json:"team_id"`
+	Level          string     `json:"level,omitempty"`
+	OpenReviews    int        `json:"open_reviews"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+	ReplacedUserID string     `json:"replaced_user_id,omitempty"`
+}
+
+type PullRequestsResponse struct {
+	PullRequests []GetPullRequest `json:"pull_requests"`
+}
+
+type ListPullRequestsRequest struct {
+	AuthorID    string    `form:"author_id"`
+	TeamName    string    `form:"team_name"`
+	Status      string    `form:"status" validate:"omitempty,oneof=DRAFT OPEN MERGED CLOSED"`
+	ReviewerID  string    `form:"reviewer_id"`
+	CreatedFrom time.Time `form:"created_from"`
+	CreatedTo   time.Time `form:"created_to"`
+	MergedFrom  time.Time `form:"merged_from"`
+	MergedTo    time.Time `form:"merged_to"`
+	Limit       int       `form:"limit" validate:"min=0"`
+	Cursor      string    `form:"cursor"`
+}
+
+type PullRequestListResponse struct {
+	PullRequests []GetPullRequest `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
+type RenamePullRequest struct {
+	ID      string `json:"pull_request_id" validate:"required"`
+	Name    string `json:"pull_request_name" validate:"required,max=255"`
+	ActorID string `json:"actor_id"`
+}
+
+type MatchedRule struct {
+	Pattern    string   `json:"pattern"`
+	Files      []string `json:"files"`
+	OwnerUsers []string `json:"owner_users,omitempty"`
+	OwnerTeams []string `json:"owner_teams,omitempty"`
+	Reviewers  []string `json:"reviewers"`
+}
+
+type PullRequestIDRequest struct {
+	ID      string `json:"pull_request_id" validate:"required"`
+	ActorID string `json:"actor_id"`
+}
+
+type ParentsRequest struct {
+	ID        string   `json:"pull_request_id" validate:"required"`
+	ParentIDs []string `json:"parent_ids" validate:"required,min=1,dive,required"`
+	ActorID   string   `json:"actor_id"`
+}
+
+// StackNode is a PR of a stack with the PRs it depends on.
+type StackNode struct {
+	ID        string     `json:"pull_request_id"`
+	Name      string     `json:"pull_request_name"`
+	AuthorID  string     `json:"author_id"`
+	Status    string     `json:"status"`
+	ParentIDs []string   `json:"parent_ids"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+}
+
+type StackResponse struct {
+	PullRequestID string      `json:"pull_request_id"`
+	PullRequests  []StackNode `json:"pull_requests"`
+}
+
+// Event is an entry of the PR timeline. Before and After hold the changed
+// values and are omitted when there was nothing before or after.
+type Event struct {
+	ID        int64           `json:"event_id"`
+	Kind      string          `json:"kind"`
+	ActorID   *string         `json:"actor_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type TimelineResponse struct {
+	PullRequestID string  `json:"pull_request_id"`
+	Events        []Event `json:"events"`
+}
+
+type ReviewersAsOfRequest struct {
+	PullRequestID string    `form:"pull_request_id" validate:"required"`
+	At            time.Time `form:"at" validate:"required"`
+}
+
+type ReviewQueueAsOfRequest struct {
+	UserID string    `form:"user_id" validate:"required"`
+	At     time.Time `form:"at" validate:"required"`
+}
+
+type AssignmentPeriod struct {
+	ReviewerID    string     `json:"reviewer_id"`
+	AssignedFrom  time.Time  `json:"assigned_from"`
+	AssignedUntil *time.Time `json:"assigned_until,omitempty"`
+}
+
+type ReviewersAsOfResponse struct {
+	PullRequestID string             `json:"pull_request_id"`
+	At            time.Time          `json:"at"`
+	Reviewers     []AssignmentPeriod `json:"reviewers"`
+}
+
+type QueuedReview struct {
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	AssignedFrom    time.Time  `json:"assigned_from"`
+	AssignedUntil   *time.Time `json:"assigned_until,omitempty"`
+}
+
+type ReviewQueueAsOfResponse struct {
+	UserID       string         `json:"user_id"`
+	At           time.Time      `json:"at"`
+	PullRequests []QueuedReview `json:"pull_requests"`
+}
+
+type AutoMergeRequest struct {
+	ID      string `json:"pull_request_id" validate:"required"`
+	Enabled bool   `json:"enabled"`
+	ActorID string `json:"actor_id"`
+}
+
+type LabelsRequest struct {
+	ID      string   `json:"pull_request_id" validate:"required"`
+	Labels  []string `json:"labels" validate:"required,min=1,dive,required,max=50"`
+	ActorID string   `json:"actor_id"`
 }
 
 type MergePRRequest struct {
-	ID string `json:"pull_request_id" validate:"required"`
+	ID            string `json:"pull_request_id" validate:"required"`
+	AdminOverride bool   `json:"admin_override"`
+	ActorID       string `json:"actor_id" validate:"required_if=AdminOverride true"`
 }
 
 type PRResponse struct {
-	ID        string    `json:"pull_request_id"`
-	Name      string    `json:"pull_request_name"`
-	AuthorID  string    `json:"author_id"`
-	Status    string    `json:"status"`
-	Reviewers []string  `json:"assigned_reviewers"`
-	MergedAt  time.Time `json:"merged_at"`
+	ID            string       `json:"pull_request_id"`
+	Name          string       `json:"pull_request_name"`
+	AuthorID      string       `json:"author_id"`
+	Status        string       `json:"status"`
+	Reviewers     []string     `json:"assigned_reviewers"`
+	Assignments   []Assignment `json:"assignments,omitempty"`
+	MergeOverride bool         `json:"merge_override"`
+	MergedBy      *string      `json:"merged_by,omitempty"`
+	AutoMerged    bool         `json:"auto_merged"`
+	MergedAt      time.Time    `json:"merged_at"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	ReviewerID    string `json:"reviewer_id" validate:"required"`
+	Verdict       string `json:"verdict" validate:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED"`
+	Comment       string `json:"comment,omitempty" validate:"max=10000"`
+}
+
+type Review struct {
+	ReviewerID  string    `json:"reviewer_id"`
+	Verdict     string    `json:"verdict"`
+	Comment     *string   `json:"comment,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type ApprovalStatus struct {
+	Approvals         int  `json:"approvals"`
+	RequiredApprovals int  `json:"required_approvals"`
+	ChangesRequested  bool `json:"changes_requested"`
+	Mergeable         bool `json:"mergeable"`
+}
+
+type SubmitReviewResponse struct {
+	PullRequestID string         `json:"pull_request_id"`
+	Review        Review         `json:"review"`
+	Status        ApprovalStatus `json:"approval_status"`
+	AutoMerged    bool           `json:"auto_merged"`
 }
 
 type ReassignRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required"`
 	UserID        string `json:"old_user_id" validate:"required"`
+	ActorID       string `json:"actor_id"`
 }
 
 type ReassignResponse struct {
-	PR         GetPullRequest `json:"pr"`
-	ReplacedBy string         `json:"replaced_by"`
+	PR           GetPullRequest `json:"pr"`
+	ReplacedBy   string         `json:"replaced_by"`
+	FromFallback bool           `json:"from_fallback"`
+}
+
+type ReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
+	ActorID       string `json:"actor_id"`
+}
+
+type DeclineRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
+	Reason        string `json:"reason" validate:"required,max=1000"`
+}
+
+type DeclineResponse struct {
+	PR           GetPullRequest `json:"pr"`
+	ReplacedBy   string         `json:"replaced_by,omitempty"`
+	FromFallback bool           `json:"from_fallback"`
+}
+
+type ScoreBreakdown struct {
+	OpenReviews    int        `json:"open_reviews"`
+	LastAssignedAt *time.Time `json:"last_assigned_at"`
+	TeamName       string     `json:"team_name"`
+	FromFallback   bool       `json:"from_fallback"`
+	MaxOpenReviews *int       `json:"max_open_reviews"`
+	FreeSlots      *int       `json:"free_slots"`
+}
+
+type SuggestedReviewer struct {
+	UserID string         `json:"user_id"`
+	Rank   int            `json:"rank"`
+	Level  string         `json:"level"`
+	Score  ScoreBreakdown `json:"score"`
+}
+
+type SuggestReviewersResponse struct {
+	PullRequestID string              `json:"pull_request_id"`
+	ReplacingUser string              `json:"replacing_user_id,omitempty"`
+	Candidates    []SuggestedReviewer `json:"candidates"`
+}
+
+type ReassignedReview struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by"`
+	FromFallback  bool   `json:"from_fallback"`
+}
+
+type ReviewReassignment struct {
+	Reassigned  []ReassignedReview `json:"reassigned"`
+	NoCandidate []string           `json:"no_candidate"`
+}
+
+type GetReviewRequest struct {
+	UserID string `form:"user_id"`
+	Status string `form:"status" validate:"omitempty,oneof=DRAFT OPEN MERGED CLOSED"`
+	Limit  int    `form:"limit" validate:"min=0"`
+	Cursor string `form:"cursor"`
+}
+
+type ReviewItem struct {
+	ID         string     `json:"pull_request_id"`
+	Name       string     `json:"pull_request_name"`
+	AuthorID   string     `json:"author_id"`
+	Status     string     `json:"status"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	Overdue    bool       `json:"overdue"`
+	CreatedAt  time.Time  `json:"created_at"`
+	MergedAt   *time.Time `json:"merged_at,omitempty"`
 }
 
 type GetReviewResponse struct {
-	UserID       string `json:"user_id"`
-	PullRequests []struct {
-		ID       string `json:"pull_request_id"`
-		Name     string `json:"pull_request_name"`
-		AuthorID string `json:"author_id"`
-		Status   string `json:"status"`
-	} `json:"pull_requests"`
+	UserID       string       `json:"user_id"`
+	PullRequests []ReviewItem `json:"pull_requests"`
+	NextCursor   string       `json:"next_cursor,omitempty"`
+}
+
+type OverdueReview struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	TeamName        string    `json:"team_name"`
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignedAt      time.Time `json:"assigned_at"`
+	DueAt           time.Time `json:"due_at"`
+	OverdueSeconds  int64     `json:"overdue_seconds"`
+}
+
+type OverdueReviewsResponse struct {
+	Overdue []OverdueReview `json:"overdue"`
 }
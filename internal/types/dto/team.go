@@ -0,0 +1,64 @@
+package dto
+
+type TeamSettings struct {
+	ReviewerCount     *int     `json:"reviewer_count,omitempty"`
+	MinReviewers      *int     `json:"min_reviewers,omitempty"`
+	SeniorReviewers   *int     `json:"senior_reviewers,omitempty"`
+	SeniorLevel       *string  `json:"senior_level,omitempty"`
+	RequiredApprovals *int     `json:"required_approvals,omitempty"`
+	ReviewSLAHours    *int     `json:"review_sla_hours,omitempty"`
+	LeadID            *string  `json:"lead_id,omitempty"`
+	FallbackTeams     []string `json:"fallback_teams,omitempty"`
+}
+
+type SetTeamSettingsRequest struct {
+	TeamName string `json:"team_name" validate:"required"`
+	TeamSettings
+}
+
+type TeamSettingsResponse struct {
+	TeamName          string   `json:"team_name"`
+	ReviewerCount     int      `json:"reviewer_count"`
+	MinReviewers      int      `json:"min_reviewers"`
+	SeniorReviewers   int      `json:"senior_reviewers"`
+	SeniorLevel       string   `json:"senior_level"`
+	RequiredApprovals int      `json:"required_approvals"`
+	ReviewSLAHours    *int     `json:"review_sla_hours"`
+	LeadID            *string  `json:"lead_id"`
+	FallbackTeams     []string `json:"fallback_teams"`
+}
+
+type OwnershipRule struct {
+	Pattern string   `json:"pattern" validate:"required"`
+	Users   []string `json:"users,omitempty"`
+	Teams   []string `json:"teams,omitempty"`
+}
+
+type SetOwnershipRulesRequest struct {
+	TeamName string          `json:"team_name" validate:"required"`
+	Rules    []OwnershipRule `json:"rules" validate:"dive"`
+}
+
+type OwnershipRulesResponse struct {
+	TeamName string          `json:"team_name"`
+	Rules    []OwnershipRule `json:"rules"`
+}
+
+type LabelPolicy struct {
+	Label             string   `json:"label" validate:"required,max=50"`
+	ReviewerCount     *int     `json:"reviewer_count,omitempty"`
+	MinReviewers      *int     `json:"min_reviewers,omitempty"`
+	RequiredApprovals *int     `json:"required_approvals,omitempty"`
+	Users             []string `json:"users,omitempty"`
+	Teams             []string `json:"teams,omitempty"`
+}
+
+type SetLabelPoliciesRequest struct {
+	TeamName string        `json:"team_name" validate:"required"`
+	Policies []LabelPolicy `json:"policies" validate:"dive"`
+}
+
+type LabelPoliciesResponse struct {
+	TeamName string        `json:"team_name"`
+	Policies []LabelPolicy `json:"policies"`
+}
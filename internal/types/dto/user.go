@@ -4,6 +4,7 @@ type User struct {
 	ID       string `json:"user_id" validate:"required"`
 	Username string `json:"username" validate:"required"`
 	IsActive bool   `json:"is_active" validate:"required"`
+	Level    string `json:"level,omitempty"`
 }
 
 type Users struct {
@@ -11,18 +12,38 @@ type Users struct {
 }
 
 type TeamWithMembers struct {
-	TeamName string `json:"team_name" validate:"required"`
-	Members  []User `json:"members" validate:"required"`
+	TeamName string        `json:"team_name" validate:"required"`
+	Members  []User        `json:"members" validate:"required"`
+	Settings *TeamSettings `json:"settings,omitempty"`
 }
 
 type SetUserIsActiveRequest struct {
-	UserID   string `json:"user_id" validate:"required"`
-	IsActive *bool  `json:"is_active" validate:"required"`
+	UserID          string `json:"user_id" validate:"required"`
+	IsActive        *bool  `json:"is_active" validate:"required"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+	ActorID         string `json:"actor_id"`
+}
+
+type SetUserIsActiveResponse struct {
+	User         UpdateUserResponse  `json:"user"`
+	Reassignment *ReviewReassignment `json:"reassignment,omitempty"`
+}
+
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id" validate:"required"`
+	MaxOpenReviews *int   `json:"max_open_reviews" validate:"omitempty,min=0"`
+}
+
+type SetLevelRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+	Level  string `json:"level" validate:"required,oneof=JUNIOR MIDDLE SENIOR LEAD"`
 }
 
 type UpdateUserResponse struct {
-	ID       string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	ID             string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	Level          string `json:"level"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
 }
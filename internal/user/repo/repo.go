@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/ilam072/avito-backend-internship/internal/types/domain"
+	"github.com/ilam072/avito-backend-internship/pkg/db"
 	"github.com/ilam072/avito-backend-internship/pkg/errutils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,19 +18,23 @@ func New(db *pgxpool.Pool) *UserRepo {
 	return &UserRepo{db: db}
 }
 
+func (r *UserRepo) conn(ctx context.Context) db.Querier {
+	return db.Conn(ctx, r.db)
+}
+
 var (
 	ErrUserNotFound = errors.New("user not found")
 )
 
 func (r *UserRepo) GetUsersByTeam(ctx context.Context, name string) ([]domain.User, error) {
 	query := `
-		SELECT u.id, u.name, u.is_active, u.team_id, u.created_at, u.updated_at
+		SELECT u.id, u.name, u.is_active, u.team_id, u.level, u.created_at, u.updated_at
 		FROM users u
 		JOIN teams t ON t.id = u.team_id
 		WHERE t.name = $1;
 	`
 
-	rows, err := r.db.Query(ctx, query, name)
+	rows, err := r.conn(ctx).Query(ctx, query, name)
 	if err != nil {
 		return nil, errutils.Wrap("failed to query users by team", err)
 	}
@@ -38,7 +43,7 @@ func (r *UserRepo) GetUsersByTeam(ctx context.Context, name string) ([]domain.Us
 	var users []domain.User
 	for rows.Next() {
 		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.IsActive, &u.TeamID, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.Username, &u.IsActive, &u.TeamID, &u.Level, &u.CreatedAt, &u.UpdatedAt); err != nil {
 			return nil, errutils.Wrap("failed to scan user", err)
 		}
 		users = append(users, u)
@@ -57,17 +62,19 @@ func (r *UserRepo) GetUsersByTeam(ctx context.Context, name string) ([]domain.Us
 
 func (r *UserRepo) GetUserByID(ctx context.Context, ID string) (domain.User, error) {
 	query := `
-		SELECT id, name, is_active, team_id, created_at, updated_at
+		SELECT id, name, is_active, team_id, level, max_open_reviews, created_at, updated_at
 		FROM users
 		WHERE id = $1;
 	`
 
 	var user domain.User
-	if err := r.db.QueryRow(ctx, query, ID).Scan(
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(
 		&user.ID,
 		&user.Username,
 		&user.IsActive,
 		&user.TeamID,
+		&user.Level,
+		&user.MaxOpenReviews,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	); err != nil {
@@ -80,37 +87,75 @@ func (r *UserRepo) GetUserByID(ctx context.Context, ID string) (domain.User, err
 	return user, nil
 }
 
-func (r *UserRepo) GetNewUserIDForPRReview(ctx context.Context, prID string, oldUserID string) (string, error) {
+func (r *UserRepo) GetReviewCandidates(ctx context.Context, filter domain.CandidateFilter) ([]domain.Candidate, error) {
 	query := `
-   		SELECT id 
-   		FROM users u 
-   		WHERE u.is_active = TRUE 
-   		  AND u.team_id = (SELECT team_id FROM users u2 WHERE u2.id = $1) 
-   		  AND u.id NOT IN (
-   		      SELECT reviewer_id FROM pr_reviewers WHERE pr_id = $2
-   		  )
-   		ORDER BY random() 
-   		LIMIT 1
+		SELECT u.id, u.team_id, u.level, COUNT(pr.id) AS open_reviews, u.max_open_reviews, MAX(r.assigned_at) AS last_assigned_at
+		FROM users u
+		LEFT JOIN pr_reviewers r ON r.reviewer_id = u.id
+		LEFT JOIN pull_requests pr ON pr.id = r.pr_id AND pr.status = 'OPEN'
+		WHERE u.is_active = TRUE
+		  AND (u.team_id = ANY($1) OR u.id = ANY($2))
+		  AND u.id <> ALL($3)
+		  AND (cardinality($4::TEXT[]) = 0 OR u.level = ANY($4))
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM absences ab
+		      WHERE ab.user_id = u.id
+		        AND ab.status IN ('SCHEDULED', 'ACTIVE')
+		        AND ab.starts_at <= NOW()
+		        AND ab.ends_at > NOW()
+		  )
+		GROUP BY u.id, u.team_id, u.level, u.max_open_reviews
+		HAVING u.max_open_reviews IS NULL OR COUNT(pr.id) < u.max_open_reviews
+		ORDER BY u.id
 	`
-	var ID string
-	if err := r.db.QueryRow(ctx, query, oldUserID, prID).Scan(&ID); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return "", ErrUserNotFound
+
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		query,
+		nonNil(filter.TeamIDs),
+		nonNil(filter.UserIDs),
+		nonNil(filter.ExcludeIDs),
+		nonNil(filter.Levels),
+	)
+	if err != nil {
+		return nil, errutils.Wrap("failed to query review candidates", err)
+	}
+	defer rows.Close()
+
+	var candidates []domain.Candidate
+	for rows.Next() {
+		var c domain.Candidate
+		if err := rows.Scan(&c.ID, &c.TeamID, &c.Level, &c.OpenReviews, &c.MaxOpenReviews, &c.LastAssignedAt); err != nil {
+			return nil, errutils.Wrap("failed to scan candidate", err)
 		}
-		return "", errutils.Wrap("failed to get new user id", err)
+		candidates = append(candidates, c)
 	}
 
-	return ID, nil
+	if err := rows.Err(); err != nil {
+		return nil, errutils.Wrap("rows iteration error", err)
+	}
+
+	return candidates, nil
 }
+
+// UpdateIsActive sets the user's state explicitly. An absence running at the
+// moment no longer restores the user when it ends, so the explicit state
+// stays.
 func (r *UserRepo) UpdateIsActive(ctx context.Context, ID string, isActive bool) error {
 	query := `
+		WITH kept AS (
+			UPDATE absences
+			SET restore_active = FALSE
+			WHERE user_id = $2 AND status = 'ACTIVE'
+		)
 		UPDATE users
 		SET is_active = $1,
 		    updated_at = NOW()
 		WHERE id = $2;
 	`
 
-	res, err := r.db.Exec(ctx, query, isActive, ID)
+	res, err := r.conn(ctx).Exec(ctx, query, isActive, ID)
 	if err != nil {
 		return errutils.Wrap("failed to update user is_active", err)
 	}
@@ -122,13 +167,62 @@ func (r *UserRepo) UpdateIsActive(ctx context.Context, ID string, isActive bool)
 	return nil
 }
 
+func (r *UserRepo) UpdateMaxOpenReviews(ctx context.Context, ID string, maxOpenReviews *int) error {
+	query := `
+		UPDATE users
+		SET max_open_reviews = $1,
+		    updated_at = NOW()
+		WHERE id = $2;
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, maxOpenReviews, ID)
+	if err != nil {
+		return errutils.Wrap("failed to update user max_open_reviews", err)
+	}
+
+	if rows := res.RowsAffected(); rows == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepo) UpdateLevel(ctx context.Context, ID string, level string) error {
+	query := `
+		UPDATE users
+		SET level = $1,
+		    updated_at = NOW()
+		WHERE id = $2;
+	`
+
+	res, err := r.conn(ctx).Exec(ctx, query, level, ID)
+	if err != nil {
+		return errutils.Wrap("failed to update user level", err)
+	}
+
+	if rows := res.RowsAffected(); rows == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepo) UserExists(ctx context.Context, ID string) (bool, error) {
 	const query = `SELECT EXISTS(SELECT 1 FROM users WHERE id=$1)`
 	var exists bool
 
-	if err := r.db.QueryRow(ctx, query, ID).Scan(&exists); err != nil {
+	if err := r.conn(ctx).QueryRow(ctx, query, ID).Scan(&exists); err != nil {
 		return false, errutils.Wrap("failed to check existing user", err)
 	}
 
 	return exists, nil
 }
+
+// nonNil keeps array parameters from being sent as NULL, which would make
+// ANY/ALL comparisons evaluate to NULL.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
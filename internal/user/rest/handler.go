@@ -13,7 +13,9 @@ import (
 )
 
 type User interface {
-	SetIsActive(ctx context.Context, ID string, isActive bool) (dto.UpdateUserResponse, error)
+	SetIsActive(ctx context.Context, ID, actorID string, isActive, reassignReviews bool) (dto.SetUserIsActiveResponse, error)
+	SetMaxOpenReviews(ctx context.Context, ID string, maxOpenReviews *int) (dto.UpdateUserResponse, error)
+	SetLevel(ctx context.Context, ID string, level string) (dto.UpdateUserResponse, error)
 	GetUsersByTeam(ctx context.Context, name string) (dto.TeamWithMembers, error)
 }
 
@@ -44,7 +46,7 @@ func (h *UserHandler) SetUserIsActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.user.SetIsActive(c.Request.Context(), req.UserID, *req.IsActive)
+	resp, err := h.user.SetIsActive(c.Request.Context(), req.UserID, req.ActorID, *req.IsActive, req.ReassignReviews)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
 			response.NotFound(c)
@@ -55,6 +57,66 @@ func (h *UserHandler) SetUserIsActive(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UserHandler) SetMaxOpenReviews(c *gin.Context) {
+	var req dto.SetMaxOpenReviewsRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	user, err := h.user.SetMaxOpenReviews(c.Request.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set user's max_open_reviews")
+		response.InternalServerError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+func (h *UserHandler) SetLevel(c *gin.Context) {
+	var req dto.SetLevelRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Logger.Warn().Err(err).Msg("failed to bind json")
+		response.BadRequest(c, "invalid request body")
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Logger.Warn().Err(err).Msg("validation error")
+		response.BadRequest(c, fmt.Sprintf("validation error: %s", err.Error()))
+		return
+	}
+
+	user, err := h.user.SetLevel(c.Request.Context(), req.UserID, req.Level)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			response.NotFound(c)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLevel) {
+			response.BadRequest(c, "invalid user level")
+			return
+		}
+		log.Logger.Error().Err(err).Any("req", req).Msg("failed to set user's level")
+		response.InternalServerError(c)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"user": user})
 }
 
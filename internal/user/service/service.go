@@ -16,16 +16,28 @@ type TeamRepo interface {
 type UserRepo interface {
 	GetUsersByTeam(ctx context.Context, name string) ([]domain.User, error)
 	UpdateIsActive(ctx context.Context, ID string, isActive bool) error
+	UpdateMaxOpenReviews(ctx context.Context, ID string, maxOpenReviews *int) error
+	UpdateLevel(ctx context.Context, ID string, level string) error
 	GetUserByID(ctx context.Context, ID string) (domain.User, error)
 }
 
+type ReviewReassigner interface {
+	ReassignOpenReviews(ctx context.Context, userID, actorID string) (dto.ReviewReassignment, error)
+}
+
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type User struct {
-	userRepo UserRepo
-	teamRepo TeamRepo
+	userRepo   UserRepo
+	teamRepo   TeamRepo
+	reassigner ReviewReassigner
+	tx         Transactor
 }
 
-func NewUser(userRepo UserRepo, teamRepo TeamRepo) *User {
-	return &User{userRepo: userRepo, teamRepo: teamRepo}
+func NewUser(userRepo UserRepo, teamRepo TeamRepo, reassigner ReviewReassigner, tx Transactor) *User {
+	return &User{userRepo: userRepo, teamRepo: teamRepo, reassigner: reassigner, tx: tx}
 }
 
 func (u *User) GetUsersByTeam(ctx context.Context, name string) (dto.TeamWithMembers, error) {
@@ -45,6 +57,7 @@ func (u *User) GetUsersByTeam(ctx context.Context, name string) (dto.TeamWithMem
 			ID:       user.ID,
 			Username: user.Username,
 			IsActive: user.IsActive,
+			Level:    user.Level,
 		}
 	}
 
@@ -54,33 +67,104 @@ func (u *User) GetUsersByTeam(ctx context.Context, name string) (dto.TeamWithMem
 	}, nil
 }
 
-func (u *User) SetIsActive(ctx context.Context, ID string, isActive bool) (dto.UpdateUserResponse, error) {
+// SetIsActive activates or deactivates the user. On deactivation the open
+// reviews of the user can be reassigned on behalf of actorID.
+func (u *User) SetIsActive(ctx context.Context, ID, actorID string, isActive, reassignReviews bool) (dto.SetUserIsActiveResponse, error) {
 	const op = "service.user.SetIsActive"
 
-	if err := u.userRepo.UpdateIsActive(ctx, ID, isActive); err != nil {
+	var reassignment *dto.ReviewReassignment
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.userRepo.UpdateIsActive(ctx, ID, isActive); err != nil {
+			if errors.Is(err, repo.ErrUserNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		if isActive || !reassignReviews {
+			return nil
+		}
+
+		result, err := u.reassigner.ReassignOpenReviews(ctx, ID, actorID)
+		if err != nil {
+			return err
+		}
+		reassignment = &result
+
+		return nil
+	})
+	if err != nil {
+		return dto.SetUserIsActiveResponse{}, errutils.Wrap(op, err)
+	}
+
+	user, err := u.getUserResponse(ctx, ID)
+	if err != nil {
+		return dto.SetUserIsActiveResponse{}, errutils.Wrap(op, err)
+	}
+
+	return dto.SetUserIsActiveResponse{User: user, Reassignment: reassignment}, nil
+}
+
+func (u *User) SetMaxOpenReviews(ctx context.Context, ID string, maxOpenReviews *int) (dto.UpdateUserResponse, error) {
+	const op = "service.user.SetMaxOpenReviews"
+
+	if err := u.userRepo.UpdateMaxOpenReviews(ctx, ID, maxOpenReviews); err != nil {
 		if errors.Is(err, repo.ErrUserNotFound) {
 			return dto.UpdateUserResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
 		}
 		return dto.UpdateUserResponse{}, errutils.Wrap(op, err)
 	}
 
-	user, err := u.userRepo.GetUserByID(ctx, ID)
+	user, err := u.getUserResponse(ctx, ID)
 	if err != nil {
+		return dto.UpdateUserResponse{}, errutils.Wrap(op, err)
+	}
+
+	return user, nil
+}
+
+func (u *User) SetLevel(ctx context.Context, ID string, level string) (dto.UpdateUserResponse, error) {
+	const op = "service.user.SetLevel"
+
+	if !domain.ValidLevel(level) {
+		return dto.UpdateUserResponse{}, errutils.Wrap(op, domain.ErrInvalidLevel)
+	}
+
+	if err := u.userRepo.UpdateLevel(ctx, ID, level); err != nil {
 		if errors.Is(err, repo.ErrUserNotFound) {
 			return dto.UpdateUserResponse{}, errutils.Wrap(op, domain.ErrUserNotFound)
 		}
 		return dto.UpdateUserResponse{}, errutils.Wrap(op, err)
 	}
 
-	teamName, err := u.teamRepo.GetTeamNameByID(ctx, user.TeamID)
+	user, err := u.getUserResponse(ctx, ID)
 	if err != nil {
 		return dto.UpdateUserResponse{}, errutils.Wrap(op, err)
 	}
 
+	return user, nil
+}
+
+func (u *User) getUserResponse(ctx context.Context, ID string) (dto.UpdateUserResponse, error) {
+	user, err := u.userRepo.GetUserByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, repo.ErrUserNotFound) {
+			return dto.UpdateUserResponse{}, domain.ErrUserNotFound
+		}
+		return dto.UpdateUserResponse{}, err
+	}
+
+	teamName, err := u.teamRepo.GetTeamNameByID(ctx, user.TeamID)
+	if err != nil {
+		return dto.UpdateUserResponse{}, err
+	}
+
 	return dto.UpdateUserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		TeamName: teamName,
-		IsActive: user.IsActive,
+		ID:             user.ID,
+		Username:       user.Username,
+		TeamName:       teamName,
+		IsActive:       user.IsActive,
+		Level:          user.Level,
+		MaxOpenReviews: user.MaxOpenReviews,
 	}, nil
 }
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS team_settings
+(
+        team_id BIGINT PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+        reviewer_count INT NOT NULL DEFAULT 2 CHECK (reviewer_count >= 0),
+        min_reviewers INT NOT NULL DEFAULT 0 CHECK (min_reviewers >= 0),
+        updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        CHECK (min_reviewers <= reviewer_count)
+);
+
+INSERT INTO team_settings (team_id)
+SELECT id FROM teams
+ON CONFLICT (team_id) DO NOTHING;
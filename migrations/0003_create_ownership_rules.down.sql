@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ownership_rules;
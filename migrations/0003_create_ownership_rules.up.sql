@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ownership_rules
+(
+        id BIGSERIAL PRIMARY KEY,
+        team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+        position INT NOT NULL,
+        pattern TEXT NOT NULL,
+        owner_user_ids TEXT[] NOT NULL DEFAULT '{}',
+        owner_team_ids BIGINT[] NOT NULL DEFAULT '{}',
+
+        UNIQUE (team_id, position)
+);
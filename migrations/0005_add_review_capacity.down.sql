@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pr_under_staffed;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS under_staffed;
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+        ADD COLUMN IF NOT EXISTS max_open_reviews INT NULL CHECK (max_open_reviews >= 0);
+
+ALTER TABLE pull_requests
+        ADD COLUMN IF NOT EXISTS under_staffed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_pr_under_staffed ON pull_requests (created_at) WHERE under_staffed;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_absences_pending;
+DROP INDEX IF EXISTS idx_absences_user_id;
+
+DROP TABLE IF EXISTS absences;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS lead_id;
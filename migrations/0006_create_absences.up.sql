@@ -0,0 +1,20 @@
+ALTER TABLE team_settings
+        ADD COLUMN IF NOT EXISTS lead_id TEXT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS absences
+(
+        id BIGSERIAL PRIMARY KEY,
+        user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        created_by TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+        ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+        reason TEXT NOT NULL DEFAULT '',
+        status VARCHAR(10) NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'ACTIVE', 'FINISHED', 'CANCELLED')),
+        restore_active BOOLEAN NULL,
+        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_absences_user_id ON absences (user_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_absences_pending ON absences (status, starts_at) WHERE status IN ('SCHEDULED', 'ACTIVE');
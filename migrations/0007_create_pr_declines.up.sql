@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pr_declines
+(
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        user_id TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        reason TEXT NOT NULL,
+        declined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        PRIMARY KEY (pr_id, user_id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE team_settings
+        DROP COLUMN IF EXISTS senior_level,
+        DROP COLUMN IF EXISTS senior_reviewers;
+
+ALTER TABLE users DROP COLUMN IF EXISTS level;
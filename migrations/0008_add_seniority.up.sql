@@ -0,0 +1,8 @@
+ALTER TABLE users
+        ADD COLUMN IF NOT EXISTS level VARCHAR(10) NOT NULL DEFAULT 'MIDDLE'
+                CHECK (level IN ('JUNIOR', 'MIDDLE', 'SENIOR', 'LEAD'));
+
+ALTER TABLE team_settings
+        ADD COLUMN IF NOT EXISTS senior_reviewers INT NOT NULL DEFAULT 0 CHECK (senior_reviewers >= 0),
+        ADD COLUMN IF NOT EXISTS senior_level VARCHAR(10) NOT NULL DEFAULT 'SENIOR'
+                CHECK (senior_level IN ('JUNIOR', 'MIDDLE', 'SENIOR', 'LEAD'));
@@ -0,0 +1,4 @@
+ALTER TABLE pr_reviewers
+        DROP COLUMN IF EXISTS factors,
+        DROP COLUMN IF EXISTS pool_size,
+        DROP COLUMN IF EXISTS strategy;
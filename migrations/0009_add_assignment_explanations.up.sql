@@ -0,0 +1,4 @@
+ALTER TABLE pr_reviewers
+        ADD COLUMN IF NOT EXISTS strategy VARCHAR(20) NOT NULL DEFAULT 'unknown',
+        ADD COLUMN IF NOT EXISTS pool_size INT NOT NULL DEFAULT 0,
+        ADD COLUMN IF NOT EXISTS factors JSONB NOT NULL DEFAULT '{}'::JSONB;
@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests
+        DROP COLUMN IF EXISTS merged_by,
+        DROP COLUMN IF EXISTS merge_override;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS required_approvals;
+
+DROP TABLE IF EXISTS pr_reviews;
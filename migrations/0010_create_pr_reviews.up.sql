@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS pr_reviews
+(
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        verdict VARCHAR(20) NOT NULL CHECK (verdict IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+        comment TEXT NULL,
+        submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        PRIMARY KEY (pr_id, reviewer_id)
+);
+
+ALTER TABLE team_settings
+        ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 1 CHECK (required_approvals >= 0);
+
+ALTER TABLE pull_requests
+        ADD COLUMN IF NOT EXISTS merge_override BOOLEAN NOT NULL DEFAULT FALSE,
+        ADD COLUMN IF NOT EXISTS merged_by TEXT NULL REFERENCES users(id) ON DELETE RESTRICT;
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+
+ALTER TABLE pull_requests
+        ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+        ADD COLUMN IF NOT EXISTS changed_files TEXT[] NOT NULL DEFAULT '{}',
+        ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE NULL;
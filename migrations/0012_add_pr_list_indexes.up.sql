@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_pr_created_id ON pull_requests (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pr_author_created_id ON pull_requests (author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pr_status_created_id ON pull_requests (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pr_merged_at ON pull_requests (merged_at) WHERE merged_at IS NOT NULL;
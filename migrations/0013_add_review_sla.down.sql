@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_due_at;
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS due_at;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS review_sla_hours;
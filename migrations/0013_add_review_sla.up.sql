@@ -0,0 +1,7 @@
+ALTER TABLE team_settings
+        ADD COLUMN IF NOT EXISTS review_sla_hours INT NULL CHECK (review_sla_hours > 0);
+
+ALTER TABLE pr_reviewers
+        ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_due_at ON pr_reviewers (due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_pending_escalation;
+
+ALTER TABLE pr_reviewers
+        DROP COLUMN IF EXISTS escalated_at,
+        DROP COLUMN IF EXISTS reminded_at;
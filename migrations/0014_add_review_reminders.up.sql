@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewers
+        ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP WITH TIME ZONE NULL,
+        ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_pending_escalation ON pr_reviewers (assigned_at) WHERE escalated_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS label_policies;
+DROP TABLE IF EXISTS pr_labels;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS pr_labels
+(
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        label VARCHAR(50) NOT NULL,
+        added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        PRIMARY KEY (pr_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_labels_label ON pr_labels (label);
+
+CREATE TABLE IF NOT EXISTS label_policies
+(
+        team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+        label VARCHAR(50) NOT NULL,
+        reviewer_count INT NULL CHECK (reviewer_count >= 0),
+        min_reviewers INT NULL CHECK (min_reviewers >= 0),
+        required_approvals INT NULL CHECK (required_approvals >= 0),
+        group_user_ids TEXT[] NOT NULL DEFAULT '{}',
+        group_team_ids BIGINT[] NOT NULL DEFAULT '{}',
+
+        PRIMARY KEY (team_id, label)
+);
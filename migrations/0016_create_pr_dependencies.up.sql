@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pr_dependencies
+(
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        parent_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+        PRIMARY KEY (pr_id, parent_id),
+        CHECK (pr_id <> parent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_dependencies_parent_id ON pr_dependencies (parent_id);
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+        DROP COLUMN IF EXISTS auto_merged,
+        DROP COLUMN IF EXISTS auto_merge;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+        ADD COLUMN IF NOT EXISTS auto_merge BOOLEAN NOT NULL DEFAULT FALSE,
+        ADD COLUMN IF NOT EXISTS auto_merged BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_pr_comments_thread_id;
+DROP INDEX IF EXISTS idx_pr_comment_threads_pr_id;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS has_unresolved_threads;
+
+DROP TABLE IF EXISTS pr_comments;
+DROP TABLE IF EXISTS pr_comment_threads;
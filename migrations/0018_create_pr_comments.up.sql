@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS pr_comment_threads
+(
+        id BIGSERIAL PRIMARY KEY,
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        created_by TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        resolved_by TEXT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        resolved_at TIMESTAMP WITH TIME ZONE NULL,
+        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS pr_comments
+(
+        id BIGSERIAL PRIMARY KEY,
+        thread_id BIGINT NOT NULL REFERENCES pr_comment_threads(id) ON DELETE CASCADE,
+        author_id TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        body TEXT NOT NULL,
+        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE pull_requests
+        ADD COLUMN IF NOT EXISTS has_unresolved_threads BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_pr_comment_threads_pr_id ON pr_comment_threads (pr_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_pr_comments_thread_id ON pr_comments (thread_id, created_at);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS pr_events_append_only ON pr_events;
+DROP FUNCTION IF EXISTS pr_events_append_only();
+
+DROP INDEX IF EXISTS idx_pr_events_pr_id;
+
+DROP TABLE IF EXISTS pr_events;
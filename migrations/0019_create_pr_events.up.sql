@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS pr_events
+(
+        id BIGSERIAL PRIMARY KEY,
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE RESTRICT,
+        kind VARCHAR(20) NOT NULL CHECK (kind IN ('CREATED', 'ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'DECLINED', 'STATUS_CHANGED', 'MERGED', 'VERDICT')),
+        actor_id TEXT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        before JSONB NULL,
+        after JSONB NULL,
+        created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pr_id ON pr_events (pr_id, created_at, id);
+
+CREATE OR REPLACE FUNCTION pr_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+        RAISE EXCEPTION 'pr_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER pr_events_append_only
+        BEFORE UPDATE OR DELETE ON pr_events
+        FOR EACH ROW EXECUTE FUNCTION pr_events_append_only();
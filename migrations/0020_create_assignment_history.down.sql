@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pr_assignment_history_current;
+DROP INDEX IF EXISTS idx_pr_assignment_history_reviewer_id;
+DROP INDEX IF EXISTS idx_pr_assignment_history_pr_id;
+
+DROP TABLE IF EXISTS pr_assignment_history;
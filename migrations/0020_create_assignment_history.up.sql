@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS pr_assignment_history
+(
+        id BIGSERIAL PRIMARY KEY,
+        pr_id TEXT NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+        reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+        valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+        valid_to TIMESTAMP WITH TIME ZONE NULL,
+
+        CHECK (valid_to IS NULL OR valid_to >= valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_assignment_history_pr_id ON pr_assignment_history (pr_id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_pr_assignment_history_reviewer_id ON pr_assignment_history (reviewer_id, valid_from);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pr_assignment_history_current ON pr_assignment_history (pr_id, reviewer_id) WHERE valid_to IS NULL;
+
+-- Assignments replaced or dropped since the timeline exists, then the current ones.
+INSERT INTO pr_assignment_history (pr_id, reviewer_id, valid_from, valid_to)
+SELECT pr_id, before->>'reviewer_id', (before->>'assigned_at')::TIMESTAMP WITH TIME ZONE, created_at
+FROM pr_events
+WHERE kind IN ('REASSIGNED', 'UNASSIGNED');
+
+INSERT INTO pr_assignment_history (pr_id, reviewer_id, valid_from)
+SELECT pr_id, reviewer_id, assigned_at
+FROM pr_reviewers;
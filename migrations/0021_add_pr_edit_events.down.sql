@@ -0,0 +1,8 @@
+ALTER TABLE pr_events DISABLE TRIGGER pr_events_append_only;
+DELETE FROM pr_events WHERE kind IN ('RENAMED', 'LABELS_ADDED', 'LABELS_REMOVED', 'PARENTS_ADDED', 'PARENTS_REMOVED', 'AUTO_MERGE_CHANGED');
+ALTER TABLE pr_events ENABLE TRIGGER pr_events_append_only;
+
+ALTER TABLE pr_events DROP CONSTRAINT IF EXISTS pr_events_kind_check;
+
+ALTER TABLE pr_events
+        ADD CONSTRAINT pr_events_kind_check CHECK (kind IN ('CREATED', 'ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'DECLINED', 'STATUS_CHANGED', 'MERGED', 'VERDICT'));
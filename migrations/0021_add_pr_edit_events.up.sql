@@ -0,0 +1,7 @@
+ALTER TABLE pr_events DROP CONSTRAINT IF EXISTS pr_events_kind_check;
+
+ALTER TABLE pr_events
+        ADD CONSTRAINT pr_events_kind_check CHECK (kind IN (
+            'CREATED', 'ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'DECLINED', 'STATUS_CHANGED', 'MERGED', 'VERDICT',
+            'RENAMED', 'LABELS_ADDED', 'LABELS_REMOVED', 'PARENTS_ADDED', 'PARENTS_REMOVED', 'AUTO_MERGE_CHANGED'
+        ));
@@ -0,0 +1,66 @@
+package glob
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrEmptyPattern = errors.New("empty pattern")
+
+// Compile converts a CODEOWNERS-style pattern into a regular expression.
+// A leading "/" anchors the pattern to the repository root, otherwise it may
+// match at any depth. "*" and "?" never cross "/", "**" does. A pattern that
+// names a directory also matches everything below it.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimSpace(pattern)
+	anchored := strings.HasPrefix(pattern, "/")
+	runes := []rune(strings.Trim(pattern, "/"))
+	if len(runes) == 0 {
+		return nil, ErrEmptyPattern
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				if i+2 < len(runes) && runes[i+2] == '/' {
+					b.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+
+	return regexp.Compile(b.String())
+}
+
+// Match reports whether path matches pattern. Invalid patterns never match.
+func Match(pattern, path string) bool {
+	re, err := Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(CleanPath(path))
+}
+
+func CleanPath(path string) string {
+	return strings.TrimLeft(strings.TrimPrefix(strings.TrimSpace(path), "./"), "/")
+}
@@ -0,0 +1,39 @@
+package glob
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/user/repo/repo.go", true},
+		{"*.go", "README.md", false},
+		{"/cmd/", "cmd/server/main.go", true},
+		{"/cmd/", "tools/cmd/main.go", false},
+		{" /docs/* ", "docs/api.md", true},
+		{" /docs/* ", "internal/docs/api.md", false},
+		{"repo/", "internal/user/repo/repo.go", true},
+		{"/internal/*/rest", "internal/user/rest/handler.go", true},
+		{"/internal/*.go", "internal/user/repo.go", false},
+		{"/internal/**/repo.go", "internal/user/repo/repo.go", true},
+		{"/internal/**/repo.go", "internal/repo.go", true},
+		{"/migrations/000?_*.sql", "migrations/0001_create_tables.up.sql", true},
+		{"docs", "./docs/api.md", true},
+		{"docs", "docs-old/api.md", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Match(tt.pattern, tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
+
+func TestCompile_EmptyPattern(t *testing.T) {
+	_, err := Compile(" / ")
+	assert.ErrorIs(t, err, ErrEmptyPattern)
+}
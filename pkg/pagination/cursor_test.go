@@ -0,0 +1,36 @@
+package pagination
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Time: time.Date(2025, 3, 1, 12, 30, 0, 123456789, time.UTC), ID: "pr|1"}
+
+	got, err := Decode(c.Encode())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, c.Time.Equal(got.Time))
+	assert.Equal(t, c.ID, got.ID)
+}
+
+func TestDecode(t *testing.T) {
+	got, err := Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	for _, token := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "YmFkLXRpbWV8aWQ"} {
+		_, err := Decode(token)
+		assert.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, Limit(0))
+	assert.Equal(t, DefaultLimit, Limit(-5))
+	assert.Equal(t, 10, Limit(10))
+	assert.Equal(t, MaxLimit, Limit(MaxLimit+1))
+}